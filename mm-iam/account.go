@@ -2,9 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/smithy-go"
 	"github.com/liuminhaw/mist-miner/shared"
 	"github.com/liuminhaw/mm-plugins/utils"
 )
@@ -32,6 +37,8 @@ func (a *accountResource) Generate(dummy utils.CacheInfo) (shared.MinerResource,
 
 // Account password policy
 type accountPasswordPolicyMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	configuration *iam.GetAccountPasswordPolicyOutput
@@ -60,6 +67,11 @@ func (pp *accountPasswordPolicyMiner) FetchConf(input any) error {
 		&iam.GetAccountPasswordPolicyInput{},
 	)
 	if err != nil {
+		// The account has no password policy, not a vanished entity
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchEntity" {
+			return &utils.MMError{Category: accountPasswordPolicy, Code: noConfig}
+		}
 		return fmt.Errorf("fetchConf passwordPolicy: %w", err)
 	}
 
@@ -95,6 +107,8 @@ func (pp *accountPasswordPolicyMiner) Generate(
 
 // Account summary
 type accountSummaryMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	configuration *iam.GetAccountSummaryOutput
@@ -149,11 +163,80 @@ func (as *accountSummaryMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerPr
 	}
 	properties = append(properties, property)
 
+	quotas, err := as.quotas()
+	if err != nil {
+		return []shared.MinerProperty{}, fmt.Errorf("generate account summary: %w", err)
+	}
+	properties = append(properties, quotas...)
+
+	return properties, nil
+}
+
+// quotas pairs the summary entries with their quota counterpart (Roles / RolesQuota,
+// Policies / PoliciesQuota, ...) and emits one property per pair with the utilization
+// percentage. The pairs reaching the quota warn threshold are logged, the threshold is kept out
+// of the content for a threshold change not to show up as a change of every quota.
+func (as *accountSummaryMiner) quotas() ([]shared.MinerProperty, error) {
+	type quotaInfo struct {
+		Usage       int32
+		Quota       int32
+		Utilization float64
+	}
+
+	properties := []shared.MinerProperty{}
+
+	summary := as.configuration.SummaryMap
+	names := []string{}
+	for key := range summary {
+		name, found := strings.CutSuffix(key, "Quota")
+		if _, ok := summary[name]; found && ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		info := quotaInfo{
+			Usage: summary[name],
+			Quota: summary[name+"Quota"],
+		}
+		if info.Quota > 0 {
+			info.Utilization = math.Round(float64(info.Usage)/float64(info.Quota)*10000) / 100
+		}
+		if info.Utilization >= as.serviceClient.quotaWarnThreshold {
+			as.Logger().Warn(
+				"account quota nearing its limit",
+				"quota", name,
+				"usage", info.Usage,
+				"limit", info.Quota,
+				"utilization", info.Utilization,
+				"warnThreshold", as.serviceClient.quotaWarnThreshold,
+			)
+		}
+
+		property := shared.MinerProperty{
+			Type: accountQuota,
+			Label: shared.MinerPropertyLabel{
+				Name:   name,
+				Unique: true,
+			},
+			Content: shared.MinerPropertyContent{
+				Format: shared.FormatJson,
+			},
+		}
+		if err := property.FormatContentValue(info); err != nil {
+			return []shared.MinerProperty{}, fmt.Errorf("account quotas: %w", err)
+		}
+		properties = append(properties, property)
+	}
+
 	return properties, nil
 }
 
 // Account alias
 type accountAliasMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	paginator     *iam.ListAccountAliasesPaginator
@@ -0,0 +1,708 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/liuminhaw/mist-miner/shared"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+// cisBenchmark evaluates the IAM section of the CIS AWS Foundations Benchmark
+// against already mined resources and the account credential report.
+type cisBenchmark struct {
+	client    *iam.Client
+	partition utils.Partition
+	now       time.Time
+
+	resources shared.MinerResources
+	// mined properties indexed by property type
+	properties map[string][]shared.MinerProperty
+	// credential report rows, nil if the report could not be read
+	report []map[string]string
+	// listing errors of the cached resource types
+	failures map[string]error
+}
+
+type cisFinding struct {
+	Control   string `json:"control"`
+	Title     string `json:"title"`
+	Status    string `json:"status"`
+	Rationale string `json:"rationale"`
+	Entity    string `json:"entity"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+type cisViolation struct {
+	entity string
+	detail string
+	// timestamp of the violation, logged but left out of the finding for its content not
+	// to change between runs
+	timestamp string
+}
+
+type cisCheck struct {
+	control   string
+	title     string
+	rationale string
+	// fromReport checks are skipped when the credential report is not available
+	fromReport bool
+	// resourceTypes are the listed resource types read by the check, a failed listing makes
+	// the account wide outcome of the check unknown
+	resourceTypes []string
+	evaluate      func(b *cisBenchmark) ([]cisViolation, error)
+}
+
+var cisChecks = []cisCheck{
+	{
+		control:    "1.4",
+		title:      "Ensure no 'root' user account access key exists",
+		rationale:  "Root access keys grant unrestricted access to the account and cannot be limited by policies.",
+		fromReport: true,
+		evaluate:   (*cisBenchmark).rootAccessKeys,
+	},
+	{
+		control:    "1.5",
+		title:      "Ensure MFA is enabled for the 'root' user account",
+		rationale:  "MFA adds a second authentication factor to the most privileged identity of the account.",
+		fromReport: true,
+		evaluate:   (*cisBenchmark).rootMFA,
+	},
+	{
+		control:   "1.8",
+		title:     "Ensure IAM password policy requires minimum length of 14 or greater",
+		rationale: "Longer passwords are more resistant to brute force attempts.",
+		evaluate:  (*cisBenchmark).passwordMinimumLength,
+	},
+	{
+		control:   "1.9",
+		title:     "Ensure IAM password policy prevents password reuse",
+		rationale: "Preventing the reuse of the last 24 passwords limits the impact of compromised passwords.",
+		evaluate:  (*cisBenchmark).passwordReusePrevention,
+	},
+	{
+		control:    "1.10",
+		title:      "Ensure MFA is enabled for all IAM users that have a console password",
+		rationale:  "Console users without MFA can be taken over with a single leaked password.",
+		fromReport: true,
+		evaluate:   (*cisBenchmark).consoleUsersMFA,
+	},
+	{
+		control:    "1.12",
+		title:      "Ensure credentials unused for 45 days or greater are disabled",
+		rationale:  "Unused credentials widen the attack surface without serving any purpose.",
+		fromReport: true,
+		evaluate:   (*cisBenchmark).unusedCredentials,
+	},
+	{
+		control:    "1.13",
+		title:      "Ensure there is only one active access key available for any single IAM user",
+		rationale:  "A single active key per user keeps key ownership and rotation manageable.",
+		fromReport: true,
+		evaluate:   (*cisBenchmark).multipleActiveKeys,
+	},
+	{
+		control:    "1.14",
+		title:      "Ensure access keys are rotated every 90 days or less",
+		rationale:  "Rotating access keys shortens the window in which a leaked key can be used.",
+		fromReport: true,
+		evaluate:   (*cisBenchmark).accessKeyRotation,
+	},
+	{
+		control:       "1.15",
+		title:         "Ensure IAM users receive permissions only through groups",
+		rationale:     "Granting permissions through groups reduces the complexity of access management.",
+		resourceTypes: []string{iamUser},
+		evaluate:      (*cisBenchmark).userAttachedPolicies,
+	},
+	{
+		control:       "1.16",
+		title:         "Ensure IAM policies that allow full \"*:*\" administrative privileges are not attached",
+		rationale:     "Least privilege should be granted instead of full administrative access.",
+		resourceTypes: []string{iamPolicy, iamUser, iamGroup, iamRole},
+		evaluate:      (*cisBenchmark).fullAdminPolicies,
+	},
+	{
+		control:       "1.17",
+		title:         "Ensure a support role has been created to manage incidents with AWS Support",
+		rationale:     "A dedicated role with AWSSupportAccess allows incidents to be handled without sharing credentials.",
+		resourceTypes: []string{iamRole},
+		evaluate:      (*cisBenchmark).supportRole,
+	},
+	{
+		control:   "1.19",
+		title:     "Ensure that all the expired SSL/TLS certificates stored in AWS IAM are removed",
+		rationale: "Expired certificates may be deployed by mistake and damage the credibility of the application.",
+		evaluate:  (*cisBenchmark).expiredServerCertificates,
+	},
+}
+
+func newCISBenchmark(
+	client *iam.Client,
+	partition utils.Partition,
+	now time.Time,
+	failures map[string]error,
+) *cisBenchmark {
+	return &cisBenchmark{
+		client:     client,
+		partition:  partition,
+		now:        now,
+		properties: map[string][]shared.MinerProperty{},
+		failures:   failures,
+	}
+}
+
+// build indexes the mined properties and reads the credential report. Failing to
+// read the credential report is logged and only skips the checks relying on it.
+func (b *cisBenchmark) build(ctx context.Context, resources shared.MinerResources) {
+	b.resources = resources
+	for _, resource := range resources {
+		for _, property := range resource.Properties {
+			b.properties[property.Type] = append(b.properties[property.Type], property)
+		}
+	}
+
+	if err := b.readCredentialReport(ctx); err != nil {
+		hclog.FromContext(ctx).Warn(
+			"cis benchmark credential report not available",
+			utils.ErrorArgs(err)...,
+		)
+	}
+}
+
+func (b *cisBenchmark) readCredentialReport(ctx context.Context) error {
+	for i := 0; ; i++ {
+		output, err := b.client.GenerateCredentialReport(ctx, &iam.GenerateCredentialReportInput{})
+		if err != nil {
+			return fmt.Errorf("readCredentialReport: %w", err)
+		}
+		if output.State == types.ReportStateTypeComplete {
+			break
+		}
+		if i >= credentialReportRetries {
+			return fmt.Errorf("readCredentialReport: report generation not completed")
+		}
+		time.Sleep(credentialReportInterval)
+	}
+
+	output, err := b.client.GetCredentialReport(ctx, &iam.GetCredentialReportInput{})
+	if err != nil {
+		return fmt.Errorf("readCredentialReport: %w", err)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(output.Content)).ReadAll()
+	if err != nil {
+		return fmt.Errorf("readCredentialReport: %w", err)
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("readCredentialReport: empty report")
+	}
+
+	b.report = []map[string]string{}
+	for _, record := range records[1:] {
+		row := map[string]string{}
+		for i, column := range records[0] {
+			if i < len(record) {
+				row[column] = record[i]
+			}
+		}
+		b.report = append(b.report, row)
+	}
+
+	return nil
+}
+
+// resource generates the CISBenchmark resource with one Finding property per
+// control, or per offending entity of a failed control. A control reading a resource
+// type that failed to be listed keeps its offending entities, its account wide outcome
+// is UNKNOWN.
+func (b *cisBenchmark) resource(ctx context.Context) (shared.MinerResource, error) {
+	resource := shared.MinerResource{Identifier: cisBenchmarkResource}
+	logger := hclog.FromContext(ctx)
+
+	for _, check := range cisChecks {
+		if check.fromReport && b.report == nil {
+			logger.Info(
+				"cis benchmark control skipped without credential report",
+				"control", check.control,
+			)
+			continue
+		}
+
+		violations, err := check.evaluate(b)
+		if err != nil {
+			return shared.MinerResource{}, fmt.Errorf("cis control %s: %w", check.control, err)
+		}
+
+		// violations of the same entity are merged into a single finding
+		findings := []cisFinding{}
+		entityFinding := map[string]int{}
+		for _, violation := range violations {
+			logger.Debug(
+				"cis benchmark violation",
+				"control", check.control,
+				"entity", violation.entity,
+				"detail", violation.detail,
+				"timestamp", violation.timestamp,
+			)
+			if i, ok := entityFinding[violation.entity]; ok {
+				findings[i].Detail = fmt.Sprintf("%s; %s", findings[i].Detail, violation.detail)
+				continue
+			}
+			entityFinding[violation.entity] = len(findings)
+			findings = append(findings, cisFinding{
+				Control:   check.control,
+				Title:     check.title,
+				Status:    cisStatusFail,
+				Rationale: check.rationale,
+				Entity:    violation.entity,
+				Detail:    violation.detail,
+			})
+		}
+		if failed := b.failedListings(check); len(failed) > 0 {
+			// Account wide conclusions need every entity to be listed
+			findings = slices.DeleteFunc(findings, func(finding cisFinding) bool {
+				return finding.Entity == cisEntityAccount
+			})
+			findings = append(findings, cisFinding{
+				Control:   check.control,
+				Title:     check.title,
+				Status:    cisStatusUnknown,
+				Rationale: check.rationale,
+				Entity:    cisEntityAccount,
+				Detail:    fmt.Sprintf("listing failed: %s", strings.Join(failed, ", ")),
+			})
+		} else if len(findings) == 0 {
+			findings = append(findings, cisFinding{
+				Control:   check.control,
+				Title:     check.title,
+				Status:    cisStatusPass,
+				Rationale: check.rationale,
+				Entity:    cisEntityAccount,
+			})
+		}
+
+		for _, finding := range findings {
+			property := shared.MinerProperty{
+				Type: cisFindingProperty,
+				Label: shared.MinerPropertyLabel{
+					Name:   fmt.Sprintf("%s%s%s", finding.Control, valueSeparator, finding.Entity),
+					Unique: true,
+				},
+				Content: shared.MinerPropertyContent{
+					Format: shared.FormatJson,
+				},
+			}
+			if err := property.FormatContentValue(finding); err != nil {
+				return shared.MinerResource{}, fmt.Errorf("cis control %s: %w", check.control, err)
+			}
+			resource.Properties = append(resource.Properties, property)
+		}
+	}
+	resource.Sort()
+
+	return resource, nil
+}
+
+// failedListings returns the resource types read by the check that failed to be listed
+func (b *cisBenchmark) failedListings(check cisCheck) []string {
+	failed := []string{}
+	for _, resourceType := range check.resourceTypes {
+		if _, ok := b.failures[resourceType]; ok {
+			failed = append(failed, resourceType)
+		}
+	}
+	return failed
+}
+
+func (b *cisBenchmark) rootRow() map[string]string {
+	for _, row := range b.report {
+		if row["user"] == credentialReportRootUser {
+			return row
+		}
+	}
+	return nil
+}
+
+func (b *cisBenchmark) userRows() []map[string]string {
+	rows := []map[string]string{}
+	for _, row := range b.report {
+		if row["user"] != credentialReportRootUser {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// olderThan reports whether a credential report timestamp is older than the given
+// number of days. Values like N/A or no_information are never older.
+func (b *cisBenchmark) olderThan(value string, days int) bool {
+	timestamp, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return false
+	}
+	return b.now.Sub(timestamp) > time.Duration(days)*24*time.Hour
+}
+
+func (b *cisBenchmark) rootAccessKeys() ([]cisViolation, error) {
+	root := b.rootRow()
+	if root == nil {
+		return nil, nil
+	}
+
+	violations := []cisViolation{}
+	for _, key := range []string{"access_key_1", "access_key_2"} {
+		if root[key+"_active"] == "true" {
+			violations = append(violations, cisViolation{
+				entity: root["arn"],
+				detail: fmt.Sprintf("%s is active", key),
+			})
+		}
+	}
+	return violations, nil
+}
+
+func (b *cisBenchmark) rootMFA() ([]cisViolation, error) {
+	root := b.rootRow()
+	if root == nil || root["mfa_active"] == "true" {
+		return nil, nil
+	}
+	return []cisViolation{{entity: root["arn"], detail: "mfa is not active"}}, nil
+}
+
+func (b *cisBenchmark) passwordPolicy() (*types.PasswordPolicy, error) {
+	properties := b.properties[accountPasswordPolicy]
+	if len(properties) == 0 {
+		return nil, nil
+	}
+
+	var policy types.PasswordPolicy
+	if err := json.Unmarshal([]byte(properties[0].Content.Value), &policy); err != nil {
+		return nil, fmt.Errorf("passwordPolicy: %w", err)
+	}
+	return &policy, nil
+}
+
+func (b *cisBenchmark) passwordMinimumLength() ([]cisViolation, error) {
+	policy, err := b.passwordPolicy()
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return []cisViolation{{entity: cisEntityAccount, detail: "no password policy"}}, nil
+	}
+	if length := int(aws.ToInt32(policy.MinimumPasswordLength)); length < cisMinimumPasswordLength {
+		return []cisViolation{{
+			entity: cisEntityAccount,
+			detail: fmt.Sprintf("minimum password length is %d", length),
+		}}, nil
+	}
+	return nil, nil
+}
+
+func (b *cisBenchmark) passwordReusePrevention() ([]cisViolation, error) {
+	policy, err := b.passwordPolicy()
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return []cisViolation{{entity: cisEntityAccount, detail: "no password policy"}}, nil
+	}
+	if reuse := int(aws.ToInt32(policy.PasswordReusePrevention)); reuse < cisPasswordReusePrevention {
+		return []cisViolation{{
+			entity: cisEntityAccount,
+			detail: fmt.Sprintf("password reuse prevention is %d", reuse),
+		}}, nil
+	}
+	return nil, nil
+}
+
+func (b *cisBenchmark) consoleUsersMFA() ([]cisViolation, error) {
+	violations := []cisViolation{}
+	for _, row := range b.userRows() {
+		if row["password_enabled"] == "true" && row["mfa_active"] != "true" {
+			violations = append(violations, cisViolation{
+				entity: row["arn"],
+				detail: "console password enabled without mfa",
+			})
+		}
+	}
+	return violations, nil
+}
+
+func (b *cisBenchmark) unusedCredentials() ([]cisViolation, error) {
+	violations := []cisViolation{}
+	for _, row := range b.userRows() {
+		if row["password_enabled"] == "true" {
+			lastUsed := row["password_last_used"]
+			if lastUsed == "no_information" || lastUsed == "N/A" {
+				lastUsed = row["password_last_changed"]
+			}
+			if b.olderThan(lastUsed, cisUnusedCredentialDays) {
+				violations = append(violations, cisViolation{
+					entity: row["arn"],
+					detail: fmt.Sprintf(
+						"password not used for %d days or more",
+						cisUnusedCredentialDays,
+					),
+					timestamp: lastUsed,
+				})
+			}
+		}
+
+		for _, key := range []string{"access_key_1", "access_key_2"} {
+			if row[key+"_active"] != "true" {
+				continue
+			}
+			lastUsed := row[key+"_last_used_date"]
+			if lastUsed == "N/A" {
+				lastUsed = row[key+"_last_rotated"]
+			}
+			if b.olderThan(lastUsed, cisUnusedCredentialDays) {
+				violations = append(violations, cisViolation{
+					entity: row["arn"],
+					detail: fmt.Sprintf(
+						"%s not used for %d days or more",
+						key,
+						cisUnusedCredentialDays,
+					),
+					timestamp: lastUsed,
+				})
+			}
+		}
+	}
+	return violations, nil
+}
+
+func (b *cisBenchmark) multipleActiveKeys() ([]cisViolation, error) {
+	violations := []cisViolation{}
+	for _, row := range b.userRows() {
+		if row["access_key_1_active"] == "true" && row["access_key_2_active"] == "true" {
+			violations = append(violations, cisViolation{
+				entity: row["arn"],
+				detail: "two active access keys",
+			})
+		}
+	}
+	return violations, nil
+}
+
+func (b *cisBenchmark) accessKeyRotation() ([]cisViolation, error) {
+	violations := []cisViolation{}
+	for _, row := range b.userRows() {
+		for _, key := range []string{"access_key_1", "access_key_2"} {
+			if row[key+"_active"] != "true" {
+				continue
+			}
+			if lastRotated := row[key+"_last_rotated"]; b.olderThan(lastRotated, cisAccessKeyRotationDays) {
+				violations = append(violations, cisViolation{
+					entity: row["arn"],
+					detail: fmt.Sprintf(
+						"%s not rotated for %d days or more",
+						key,
+						cisAccessKeyRotationDays,
+					),
+					timestamp: lastRotated,
+				})
+			}
+		}
+	}
+	return violations, nil
+}
+
+func (b *cisBenchmark) userAttachedPolicies() ([]cisViolation, error) {
+	violations := []cisViolation{}
+	for _, resource := range b.resources {
+		user, ok := b.entity(resource, userDetail)
+		if !ok {
+			continue
+		}
+		for _, property := range resource.Properties {
+			switch property.Type {
+			case userInlinePolicy:
+				violations = append(violations, cisViolation{
+					entity: user.Arn,
+					detail: fmt.Sprintf("inline policy %s", property.Label.Name),
+				})
+			case userManagedPolicy:
+				violations = append(violations, cisViolation{
+					entity: user.Arn,
+					detail: fmt.Sprintf("managed policy %s", property.Label.Name),
+				})
+			}
+		}
+	}
+	return violations, nil
+}
+
+// fullAdminPolicies finds attached customer managed policies whose default version
+// allows all actions on all resources, and inline policies doing the same.
+func (b *cisBenchmark) fullAdminPolicies() ([]cisViolation, error) {
+	violations := []cisViolation{}
+	for _, resource := range b.resources {
+		if _, ok := b.entity(resource, policyDetail); ok {
+			violation, err := b.fullAdminManagedPolicy(resource)
+			if err != nil {
+				return nil, fmt.Errorf("fullAdminPolicies: %w", err)
+			}
+			if violation != nil {
+				violations = append(violations, *violation)
+			}
+			continue
+		}
+
+		var owner graphEntity
+		var found bool
+		for _, detailType := range []string{userDetail, groupDetail, roleDetail} {
+			if owner, found = b.entity(resource, detailType); found {
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+		for _, property := range resource.Properties {
+			switch property.Type {
+			case userInlinePolicy, groupInlinePolicy, roleInlinePolicy:
+			default:
+				continue
+			}
+			var inline struct {
+				PolicyName     string
+				PolicyDocument string
+			}
+			if err := json.Unmarshal([]byte(property.Content.Value), &inline); err != nil {
+				return nil, fmt.Errorf("fullAdminPolicies: %w", err)
+			}
+			document, err := utils.ParsePolicyDocument(inline.PolicyDocument)
+			if err != nil {
+				return nil, fmt.Errorf("fullAdminPolicies: %w", err)
+			}
+			if allowsFullAdmin(document) {
+				violations = append(violations, cisViolation{
+					entity: owner.Arn,
+					detail: fmt.Sprintf("inline policy %s allows *:*", inline.PolicyName),
+				})
+			}
+		}
+	}
+	return violations, nil
+}
+
+func (b *cisBenchmark) fullAdminManagedPolicy(resource shared.MinerResource) (*cisViolation, error) {
+	var policy types.Policy
+	for _, property := range resource.Properties {
+		if property.Type == policyDetail {
+			if err := json.Unmarshal([]byte(property.Content.Value), &policy); err != nil {
+				return nil, fmt.Errorf("fullAdminManagedPolicy: %w", err)
+			}
+		}
+	}
+	if aws.ToInt32(policy.AttachmentCount) == 0 {
+		return nil, nil
+	}
+
+	for _, property := range resource.Properties {
+		if property.Type != policyVersions ||
+			property.Label.Name != aws.ToString(policy.DefaultVersionId) {
+			continue
+		}
+		var version types.PolicyVersion
+		if err := json.Unmarshal([]byte(property.Content.Value), &version); err != nil {
+			return nil, fmt.Errorf("fullAdminManagedPolicy: %w", err)
+		}
+		document, err := utils.ParsePolicyDocument(aws.ToString(version.Document))
+		if err != nil {
+			return nil, fmt.Errorf("fullAdminManagedPolicy: %w", err)
+		}
+		if allowsFullAdmin(document) {
+			return &cisViolation{
+				entity: aws.ToString(policy.Arn),
+				detail: fmt.Sprintf(
+					"default version %s allows *:* and is attached %d times",
+					aws.ToString(version.VersionId),
+					aws.ToInt32(policy.AttachmentCount),
+				),
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+func allowsFullAdmin(document utils.PolicyDocument) bool {
+	for _, statement := range document.Statement {
+		if statement.Effect != "Allow" {
+			continue
+		}
+		if slices.Contains(statement.Action, "*") && slices.Contains(statement.Resource, "*") {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *cisBenchmark) supportRole() ([]cisViolation, error) {
+	for _, property := range b.properties[roleManagedPolicy] {
+		var attached graphAttachedPolicy
+		if err := json.Unmarshal([]byte(property.Content.Value), &attached); err != nil {
+			return nil, fmt.Errorf("supportRole: %w", err)
+		}
+		if attached.PolicyArn == b.partition.Arn("iam", "", "aws", cisSupportPolicy) {
+			return nil, nil
+		}
+	}
+	return []cisViolation{{
+		entity: cisEntityAccount,
+		detail: "no role with AWSSupportAccess policy attached",
+	}}, nil
+}
+
+func (b *cisBenchmark) expiredServerCertificates() ([]cisViolation, error) {
+	violations := []cisViolation{}
+	for _, property := range b.properties[serverCertificateDetail] {
+		var certificate types.ServerCertificate
+		if err := json.Unmarshal([]byte(property.Content.Value), &certificate); err != nil {
+			return nil, fmt.Errorf("expiredServerCertificates: %w", err)
+		}
+		metadata := certificate.ServerCertificateMetadata
+		if metadata == nil || metadata.Expiration == nil {
+			continue
+		}
+		if metadata.Expiration.Before(b.now) {
+			violations = append(violations, cisViolation{
+				entity:    aws.ToString(metadata.Arn),
+				detail:    "certificate expired",
+				timestamp: metadata.Expiration.Format(time.RFC3339),
+			})
+		}
+	}
+	return violations, nil
+}
+
+// entity returns the decoded detail property of the given type of a mined resource.
+func (b *cisBenchmark) entity(
+	resource shared.MinerResource,
+	detailType string,
+) (graphEntity, bool) {
+	for _, property := range resource.Properties {
+		if property.Type != detailType {
+			continue
+		}
+		var entity graphEntity
+		if err := json.Unmarshal([]byte(property.Content.Value), &entity); err != nil {
+			return graphEntity{}, false
+		}
+		return entity, true
+	}
+	return graphEntity{}, false
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+func TestCISBenchmarkResourceStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures map[string]error
+		want     map[string]string
+	}{
+		{
+			name:     "all listed",
+			failures: map[string]error{},
+			want: map[string]string{
+				"1.15": cisStatusPass,
+				"1.16": cisStatusPass,
+				"1.17": cisStatusFail,
+				"1.19": cisStatusPass,
+			},
+		},
+		{
+			name:     "roles listing failed",
+			failures: map[string]error{iamRole: errors.New("AccessDenied")},
+			want: map[string]string{
+				"1.15": cisStatusPass,
+				"1.16": cisStatusUnknown,
+				"1.17": cisStatusUnknown,
+				"1.19": cisStatusPass,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			benchmark := newCISBenchmark(nil, utils.PartitionAws, time.Now(), tt.failures)
+			resource, err := benchmark.resource(context.Background())
+			if err != nil {
+				t.Fatalf("resource() error = %v", err)
+			}
+
+			got := map[string]string{}
+			for _, property := range resource.Properties {
+				var finding cisFinding
+				if err := json.Unmarshal([]byte(property.Content.Value), &finding); err != nil {
+					t.Fatalf("finding %s: %v", property.Label.Name, err)
+				}
+				if finding.Entity == cisEntityAccount {
+					got[finding.Control] = finding.Status
+				}
+			}
+			for control, status := range tt.want {
+				if got[control] != status {
+					t.Errorf("control %s status = %q, want %q", control, got[control], status)
+				}
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package main
 
+import "time"
+
 const (
 	// users
 	userDetail                    = "UserDetail"
@@ -28,11 +30,15 @@ const (
 	roleInlinePolicy    = "RoleInlinePolicy"
 	roleManagedPolicy   = "RoleManagedPolicy"
 	roleInstanceProfile = "RoleInstanceProfile"
+	rolePermissionSet   = "RolePermissionSet"
 
 	// Account
 	accountPasswordPolicy = "AccountPasswordPolicy"
 	accountSummary        = "AccountSummary"
 	accountAlias          = "AccountAlias"
+	accountQuota          = "AccountQuota"
+
+	defaultQuotaWarnThreshold = 80
 
 	// SSO Provider
 	ssoOIDCProvider = "OIDCProvider"
@@ -48,6 +54,38 @@ const (
 	// Instance Profile
 	instanceProfileDetail = "InstanceProfileDetail"
 
+	// IAM graph
+	iamGraphResource         = "IAMGraph"
+	graphNodeUser            = "User"
+	graphNodeGroup           = "Group"
+	graphNodeRole            = "Role"
+	graphNodePolicy          = "Policy"
+	graphNodeInstanceProfile = "InstanceProfile"
+	graphNodePrincipal       = "Principal"
+	graphEdgeMemberOf        = "member-of"
+	graphEdgeAttached        = "attached"
+	graphEdgeTrusts          = "trusts"
+	graphEdgeBoundary        = "boundary"
+	graphEdgeProfileOf       = "profile-of"
+
+	// CIS benchmark
+	cisBenchmarkResource       = "CISBenchmark"
+	cisFindingProperty         = "Finding"
+	cisStatusPass              = "PASS"
+	cisStatusFail              = "FAIL"
+	cisStatusUnknown           = "UNKNOWN"
+	cisEntityAccount           = "Account"
+	cisSupportPolicy           = "policy/AWSSupportAccess"
+	cisMinimumPasswordLength   = 14
+	cisPasswordReusePrevention = 24
+	cisUnusedCredentialDays    = 45
+	cisAccessKeyRotationDays   = 90
+
+	// credential report
+	credentialReportRootUser = "<root_account>"
+	credentialReportRetries  = 10
+	credentialReportInterval = 2 * time.Second
+
 	// crawlers
 	iamGroup             = "Groups"
 	iamUser              = "Users"
@@ -59,14 +97,28 @@ const (
 	iamVirtualMFADevice  = "VirtualMFADevice"
 	iamInstanceProfile   = "InstanceProfile"
 
+	// Roles provisioned by IAM Identity Center permission sets
+	reservedSSORolePrefix = "AWSReservedSSO_"
+
 	noConfig = "NoConfiguration"
 	noProps  = "NoProperties"
 
 	// equipments
 	policyEquipmentType     = "policies"
 	virtualMFAEquipmentType = "virtualMFADevices"
+	graphEquipmentType      = "graph"
+	benchmarkEquipmentType  = "benchmark"
+	accountEquipmentType    = "account"
+
+	valueSeparator = "|"
 )
 
+// Property type of the MiningError recorded when a resource type could not be listed
+const listingProperty = "Listing"
+
+// Name of the service in the retry equipment
+const iamRetryService = "iam"
+
 var miningResources = []string{
 	iamUser,
 	iamGroup,
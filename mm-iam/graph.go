@@ -0,0 +1,312 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/liuminhaw/mist-miner/shared"
+	iamContext "github.com/liuminhaw/mm-plugins/mm-iam/context"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+// iamGraph builds the relationship graph between users, groups, roles,
+// instance profiles and policies from already mined resources.
+//
+// Edge directions:
+//   - member-of:  user -> group
+//   - attached:   user / group / role -> managed policy
+//   - boundary:   user / role -> permissions boundary policy
+//   - trusts:     role -> principal allowed to assume the role
+//   - profile-of: instance profile -> role
+type iamGraph struct {
+	graph *utils.Graph
+	// user id to user arn, used to resolve GroupUser properties
+	userArns map[string]string
+}
+
+type graphEntity struct {
+	Arn                 string
+	UserId              string
+	UserName            string
+	GroupName           string
+	RoleName            string
+	PolicyName          string
+	InstanceProfileName string
+	PermissionsBoundary *struct {
+		PermissionsBoundaryArn string
+	}
+	AssumeRolePolicyDocument string
+	Roles                    []struct {
+		Arn string
+	}
+}
+
+type graphAttachedPolicy struct {
+	PolicyArn  string
+	PolicyName string
+}
+
+type graphMember struct {
+	Name string `json:"name"`
+	Id   string `json:"id"`
+	Arn  string `json:"arn"`
+}
+
+func newIAMGraph() *iamGraph {
+	return &iamGraph{graph: utils.NewGraph(), userArns: map[string]string{}}
+}
+
+// build walks the mined resources twice, first collecting the entity nodes
+// then adding the relationship edges between them.
+func (g *iamGraph) build(ctx context.Context, resources shared.MinerResources) error {
+	for _, resource := range resources {
+		for _, property := range resource.Properties {
+			if err := g.addEntityNode(property); err != nil {
+				return fmt.Errorf("graph build %s: %w", resource.Identifier, err)
+			}
+		}
+	}
+
+	for _, resource := range resources {
+		source := g.resourceArn(resource)
+		if source == "" {
+			continue
+		}
+
+		for _, property := range resource.Properties {
+			if err := g.addPropertyEdges(ctx, source, property); err != nil {
+				return fmt.Errorf("graph build %s: %w", resource.Identifier, err)
+			}
+		}
+	}
+	g.graph.Sort()
+
+	return nil
+}
+
+func (g *iamGraph) addEntityNode(property shared.MinerProperty) error {
+	var nodeType string
+	switch property.Type {
+	case userDetail:
+		nodeType = graphNodeUser
+	case groupDetail:
+		nodeType = graphNodeGroup
+	case roleDetail:
+		nodeType = graphNodeRole
+	case policyDetail:
+		nodeType = graphNodePolicy
+	case instanceProfileDetail:
+		nodeType = graphNodeInstanceProfile
+	default:
+		return nil
+	}
+
+	var entity graphEntity
+	if err := json.Unmarshal([]byte(property.Content.Value), &entity); err != nil {
+		return fmt.Errorf("addEntityNode: %w", err)
+	}
+
+	label := entity.UserName
+	for _, name := range []string{
+		entity.GroupName, entity.RoleName, entity.PolicyName, entity.InstanceProfileName,
+	} {
+		if label == "" {
+			label = name
+		}
+	}
+	g.graph.AddNode(utils.GraphNode{Id: entity.Arn, Type: nodeType, Label: label})
+
+	if nodeType == graphNodeUser {
+		g.userArns[entity.UserId] = entity.Arn
+	}
+
+	return nil
+}
+
+// resourceArn returns the arn of the resource's detail property, which is
+// used as the source node of the resource's edges.
+func (g *iamGraph) resourceArn(resource shared.MinerResource) string {
+	for _, property := range resource.Properties {
+		switch property.Type {
+		case userDetail, groupDetail, roleDetail, policyDetail, instanceProfileDetail:
+			var entity graphEntity
+			if err := json.Unmarshal([]byte(property.Content.Value), &entity); err != nil {
+				return ""
+			}
+			return entity.Arn
+		}
+	}
+
+	return ""
+}
+
+func (g *iamGraph) addPropertyEdges(
+	ctx context.Context,
+	source string,
+	property shared.MinerProperty,
+) error {
+	switch property.Type {
+	case userGroups:
+		var group graphEntity
+		if err := json.Unmarshal([]byte(property.Content.Value), &group); err != nil {
+			return fmt.Errorf("addPropertyEdges %s: %w", property.Type, err)
+		}
+		g.graph.AddNode(utils.GraphNode{Id: group.Arn, Type: graphNodeGroup, Label: group.GroupName})
+		g.graph.AddEdge(utils.GraphEdge{Source: source, Target: group.Arn, Type: graphEdgeMemberOf})
+	case groupUser:
+		var member graphMember
+		if err := json.Unmarshal([]byte(property.Content.Value), &member); err != nil {
+			return fmt.Errorf("addPropertyEdges %s: %w", property.Type, err)
+		}
+		if userArn, ok := g.userArns[member.Id]; ok {
+			g.graph.AddEdge(utils.GraphEdge{Source: userArn, Target: source, Type: graphEdgeMemberOf})
+		} else {
+			hclog.FromContext(ctx).Debug(
+				"graph group member not mined, skipped",
+				"user", member.Name,
+				"group", source,
+			)
+		}
+	case userManagedPolicy, groupManagedPolicy, roleManagedPolicy:
+		var attached graphAttachedPolicy
+		if err := json.Unmarshal([]byte(property.Content.Value), &attached); err != nil {
+			return fmt.Errorf("addPropertyEdges %s: %w", property.Type, err)
+		}
+		g.graph.AddNode(utils.GraphNode{
+			Id:    attached.PolicyArn,
+			Type:  graphNodePolicy,
+			Label: attached.PolicyName,
+		})
+		g.graph.AddEdge(utils.GraphEdge{
+			Source: source,
+			Target: attached.PolicyArn,
+			Type:   graphEdgeAttached,
+		})
+	case roleInstanceProfile:
+		var profile graphMember
+		if err := json.Unmarshal([]byte(property.Content.Value), &profile); err != nil {
+			return fmt.Errorf("addPropertyEdges %s: %w", property.Type, err)
+		}
+		g.graph.AddNode(utils.GraphNode{
+			Id:    profile.Arn,
+			Type:  graphNodeInstanceProfile,
+			Label: profile.Name,
+		})
+		g.graph.AddEdge(utils.GraphEdge{Source: profile.Arn, Target: source, Type: graphEdgeProfileOf})
+	case instanceProfileDetail:
+		var entity graphEntity
+		if err := json.Unmarshal([]byte(property.Content.Value), &entity); err != nil {
+			return fmt.Errorf("addPropertyEdges %s: %w", property.Type, err)
+		}
+		for _, role := range entity.Roles {
+			g.graph.AddEdge(utils.GraphEdge{Source: source, Target: role.Arn, Type: graphEdgeProfileOf})
+		}
+	case userDetail, roleDetail:
+		var entity graphEntity
+		if err := json.Unmarshal([]byte(property.Content.Value), &entity); err != nil {
+			return fmt.Errorf("addPropertyEdges %s: %w", property.Type, err)
+		}
+		if entity.PermissionsBoundary != nil && entity.PermissionsBoundary.PermissionsBoundaryArn != "" {
+			boundaryArn := entity.PermissionsBoundary.PermissionsBoundaryArn
+			g.graph.AddNode(utils.GraphNode{
+				Id:    boundaryArn,
+				Type:  graphNodePolicy,
+				Label: boundaryArn[strings.LastIndex(boundaryArn, "/")+1:],
+			})
+			g.graph.AddEdge(utils.GraphEdge{Source: source, Target: boundaryArn, Type: graphEdgeBoundary})
+		}
+		if property.Type == roleDetail && entity.AssumeRolePolicyDocument != "" {
+			if err := g.addTrustEdges(source, entity.AssumeRolePolicyDocument); err != nil {
+				return fmt.Errorf("addPropertyEdges %s: %w", property.Type, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (g *iamGraph) addTrustEdges(roleArn, document string) error {
+	trustPolicy, err := utils.ParsePolicyDocument(document)
+	if err != nil {
+		return fmt.Errorf("addTrustEdges: %w", err)
+	}
+
+	for _, statement := range trustPolicy.Statement {
+		if statement.Effect != "Allow" {
+			continue
+		}
+		for _, principalType := range statement.Principal.Types() {
+			for _, principal := range statement.Principal[principalType] {
+				g.graph.AddNode(utils.GraphNode{
+					Id:    principal,
+					Type:  graphNodePrincipal,
+					Label: principalType,
+				})
+				g.graph.AddEdge(utils.GraphEdge{
+					Source: roleArn,
+					Target: principal,
+					Type:   graphEdgeTrusts,
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+// resource generates the IAMGraph resource, rendering the graph in the formats
+// selected by the graph equipment.
+func (g *iamGraph) resource(ctx context.Context) (shared.MinerResource, error) {
+	exportFormat := utils.GetEquipAttribute(
+		iamContext.Equipments(ctx),
+		utils.EquipmentInfo{
+			TargetType: graphEquipmentType,
+			TargetName: "export",
+			TargetAttr: "format",
+			DefaultVal: "JSON",
+			AcceptVals: []string{"JSON", "DOT", "All"},
+		},
+	)
+	hclog.FromContext(ctx).Debug("equipment", "graphExportFormat", exportFormat)
+
+	resource := shared.MinerResource{Identifier: iamGraphResource}
+
+	if exportFormat == "JSON" || exportFormat == "All" {
+		property := shared.MinerProperty{
+			Type: iamGraphResource,
+			Label: shared.MinerPropertyLabel{
+				Name:   "Graph",
+				Unique: true,
+			},
+			Content: shared.MinerPropertyContent{
+				Format: shared.FormatJson,
+			},
+		}
+		if err := property.FormatContentValue(g.graph); err != nil {
+			return shared.MinerResource{}, fmt.Errorf("graph resource: %w", err)
+		}
+		resource.Properties = append(resource.Properties, property)
+	}
+
+	if exportFormat == "DOT" || exportFormat == "All" {
+		property := shared.MinerProperty{
+			Type: iamGraphResource,
+			Label: shared.MinerPropertyLabel{
+				Name:   "DOT",
+				Unique: true,
+			},
+			Content: shared.MinerPropertyContent{
+				Format: shared.FormatText,
+			},
+		}
+		if err := property.FormatContentValue(g.graph.DOT(iamGraphResource)); err != nil {
+			return shared.MinerResource{}, fmt.Errorf("graph resource: %w", err)
+		}
+		resource.Properties = append(resource.Properties, property)
+	}
+
+	return resource, nil
+}
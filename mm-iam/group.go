@@ -35,6 +35,8 @@ func (g *groupResource) Generate(datum utils.CacheInfo) (shared.MinerResource, e
 // group detail
 // Including information about the group and its users
 type groupDetailMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	configuration *iam.GetGroupOutput
@@ -144,6 +146,8 @@ func (gd *groupDetailMiner) groupUserProp() ([]shared.MinerProperty, error) {
 // group inline policy (ListGroupPolicies)
 // Including information about the group's inline policies
 type groupInlinePolicyMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	paginator     *iam.ListGroupPoliciesPaginator
@@ -199,6 +203,10 @@ func (gip *groupInlinePolicyMiner) Generate(datum utils.CacheInfo) ([]shared.Min
 				},
 			)
 			if err != nil {
+				if utils.IsVanished(err) {
+					gip.Logger().Info("inline policy vanished during mining", "policyName", policyName)
+					continue
+				}
 				return []shared.MinerProperty{}, fmt.Errorf("generate groupInlinePolicy: %w", err)
 			}
 
@@ -234,6 +242,8 @@ func (gip *groupInlinePolicyMiner) Generate(datum utils.CacheInfo) ([]shared.Min
 // group managed policy (ListAttachedGroupPolicies)
 // Including information about the group's attached managed policies
 type groupManagedPolicyMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	paginator     *iam.ListAttachedGroupPoliciesPaginator
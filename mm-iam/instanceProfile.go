@@ -39,6 +39,8 @@ func (i *instanceProfileResource) Generate(datum utils.CacheInfo) (shared.MinerR
 
 // instanceProfile detail
 type instanceProfileDetailMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	configuration *iam.GetInstanceProfileOutput
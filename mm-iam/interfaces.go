@@ -105,6 +105,9 @@ var rolePropsCrawlerConstructors = []utils.PropsCrawlerConstructor{
 	func(client utils.Client) (utils.PropsCrawler, error) {
 		return newRoleInstanceProfileMiner(client)
 	},
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newRoleSSOPermissionSetMiner(client)
+	},
 }
 
 var accountPropsCrawlerConstructors = []utils.PropsCrawlerConstructor{
@@ -4,11 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
-	"os"
+	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
 	"github.com/liuminhaw/mist-miner/shared"
 	iamContext "github.com/liuminhaw/mm-plugins/mm-iam/context"
@@ -23,7 +24,8 @@ type Miner struct {
 }
 
 func (m Miner) Mine(mineConfig shared.MinerConfig) (shared.MinerResources, error) {
-	log.Printf("Plugin name: %s\n", PLUG_NAME)
+	logger := utils.NewLogger(PLUG_NAME, mineConfig.Equipments)
+	logger.Info("mine")
 
 	// Get authentication profile from config
 	awsAuth, err := utils.ConfigAuth(mineConfig)
@@ -36,25 +38,86 @@ func (m Miner) Mine(mineConfig shared.MinerConfig) (shared.MinerResources, error
 		config.WithSharedConfigProfile(string(awsAuth.Profile)),
 	)
 
-	serviceClient := newIAMClient(iam.NewFromConfig(cfg))
+	ctx := hclog.WithContext(context.Background(), logger)
+	if mineConfig.Equipments != nil {
+		ctx = iamContext.WithEquipments(ctx, mineConfig.Equipments)
+	}
+
+	// iam has low request rate limits, a throttled run slows down through the retry policy
+	retryPolicy := utils.NewRetryPolicy(mineConfig.Equipments, iamRetryService)
+	defer retryPolicy.LogSummary(logger)
+
+	// The run summary counts every api call, sts included
+	run := utils.NewMiningRun(retryPolicy)
+	cfg = utils.LoggerConfig(run.Config(cfg), logger)
+
+	// iam is a global service, reached through the configured region of its partition
+	accountId, partition, err := utils.CallerIdentity(cfg)
+	if errors.Is(err, utils.ErrNoRegion) {
+		return nil, fmt.Errorf("mine: %w", err)
+	}
+	if err != nil {
+		logger.Warn("failed to get caller identity", utils.ErrorArgs(err)...)
+	}
+	logger.Info("caller identity", "account", accountId, "partition", partition.Name)
+
+	serviceClient := newIAMClient(
+		iam.NewFromConfig(retryPolicy.Config(cfg)),
+		quotaWarnThreshold(ctx),
+		partition,
+		run,
+	)
 	client, err := assertIAMClient(serviceClient)
 	if err != nil {
 		return nil, fmt.Errorf("mine: %w", err)
 	}
 
-	ctx := context.Background()
-	if mineConfig.Equipments != nil {
-		ctx = iamContext.WithEquipments(ctx, mineConfig.Equipments)
+	cisMode := utils.GetEquipAttribute(
+		iamContext.Equipments(ctx),
+		utils.EquipmentInfo{
+			TargetType: benchmarkEquipmentType,
+			TargetName: "cis",
+			TargetAttr: "mode",
+			DefaultVal: "Enabled",
+			AcceptVals: []string{"Enabled", "Disabled"},
+		},
+	)
+	logger.Debug("equipment", "cisBenchmarkMode", cisMode)
+
+	// Permissions are checked before mining, for the missing ones to be told up front
+	permissionsCheck := utils.NewPermissionsCheck(mineConfig.Equipments)
+	var permissionsResource shared.MinerResource
+	if permissionsCheck.Enabled() {
+		start := time.Now()
+		permissions, err := requiredPermissions(cisMode == "Enabled")
+		if err != nil {
+			return nil, fmt.Errorf("mine: %w", err)
+		}
+		permissionsResource, err = permissionsCheck.Resource(
+			ctx,
+			retryPolicy.Config(cfg),
+			permissions,
+			logger,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("mine: %w", err)
+		}
+		run.AddResourceTypeDuration(utils.RequiredPermissionsResource, time.Since(start))
 	}
 
 	memory := newCaching()
+	vanished := utils.VanishedEntities{}
+	defer vanished.LogSummary(logger)
 
-	if err := memory.read(ctx, client.client); err != nil {
-		return nil, fmt.Errorf("mine: %w", err)
-	}
+	// Resource types that failed to be listed are recorded, the others are still mined. Each
+	// failure is recorded by the MiningError resource of its resource type below, and the
+	// joined listing error is returned along with the mined resources.
+	start := time.Now()
+	listingErr := memory.read(ctx, client.client)
+	run.AddResourceTypeDuration(listingProperty, time.Since(start))
 
 	for _, resourceType := range miningResources {
-		log.Printf("resource type: %s\n", resourceType)
+		logger.Info("mine resource type", "resourceType", resourceType)
 
 		var cachedData dataCache
 		switch resourceType {
@@ -77,27 +140,119 @@ func (m Miner) Mine(mineConfig shared.MinerConfig) (shared.MinerResources, error
 		case iamInstanceProfile:
 			cachedData = memory.instanceProfiles
 		default:
-			log.Printf("Unsupported resource type: %s\n", resourceType)
+			logger.Warn("unsupported resource type", "resourceType", resourceType)
 			continue
 		}
 
-		resourcesCrawler, err := mineResources(ctx, serviceClient, resourceType, cachedData)
+		start = time.Now()
+		resourcesCrawler, err := mineResources(
+			ctx,
+			serviceClient,
+			resourceType,
+			cachedData,
+			vanished,
+		)
 		if err != nil {
 			return nil, fmt.Errorf("mine: %w", err)
 		}
 		resources = append(resources, resourcesCrawler...)
+		run.AddResourceTypeDuration(resourceType, time.Since(start))
+
+		if listErr, ok := memory.failures[resourceType]; ok {
+			listingErr := &utils.PropsError{
+				Identifier:   resourceType,
+				PropertyType: listingProperty,
+				Err:          listErr,
+			}
+			errorResource, err := listingErr.Resource()
+			if err != nil {
+				return nil, fmt.Errorf("mine: %w", err)
+			}
+			resources = append(resources, errorResource)
+		}
+	}
+
+	start = time.Now()
+	graph := newIAMGraph()
+	if err := graph.build(ctx, resources); err != nil {
+		return nil, fmt.Errorf("mine: %w", err)
+	}
+	graphResource, err := graph.resource(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("mine: %w", err)
+	}
+	resources = append(resources, graphResource)
+	run.AddResourceTypeDuration(iamGraphResource, time.Since(start))
+
+	if cisMode == "Enabled" {
+		start = time.Now()
+		benchmark := newCISBenchmark(
+			client.client,
+			client.partition,
+			time.Now(),
+			memory.failures,
+		)
+		benchmark.build(ctx, resources)
+		benchmarkResource, err := benchmark.resource(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("mine: %w", err)
+		}
+		resources = append(resources, benchmarkResource)
+		run.AddResourceTypeDuration(cisBenchmarkResource, time.Since(start))
+	}
+
+	if permissionsCheck.Enabled() {
+		resources = append(resources, permissionsResource)
+	}
+
+	runResource, err := run.Resource(resources)
+	if err != nil {
+		return nil, fmt.Errorf("mine: %w", err)
+	}
+	resources = append(resources, runResource)
+
+	if listingErr != nil {
+		return resources, fmt.Errorf("mine: %w", listingErr)
 	}
 
 	return resources, nil
 }
 
+// quotaWarnThreshold reads the account quota warn threshold percentage from equipment,
+// falling back to the default threshold on invalid values.
+func quotaWarnThreshold(ctx context.Context) float64 {
+	value := utils.GetEquipAttribute(
+		iamContext.Equipments(ctx),
+		utils.EquipmentInfo{
+			TargetType: accountEquipmentType,
+			TargetName: "quota",
+			TargetAttr: "warnThreshold",
+			DefaultVal: strconv.Itoa(defaultQuotaWarnThreshold),
+		},
+	)
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil || threshold <= 0 || threshold > 100 {
+		hclog.FromContext(ctx).Warn(
+			"invalid quota warnThreshold, use default",
+			"warnThreshold", value,
+			"default", defaultQuotaWarnThreshold,
+		)
+		return defaultQuotaWarnThreshold
+	}
+	hclog.FromContext(ctx).Debug("equipment", "quotaWarnThreshold", threshold)
+
+	return threshold
+}
+
 func mineResources(
 	ctx context.Context,
 	client utils.Client,
 	resourceType string,
 	data dataCache,
+	vanished utils.VanishedEntities,
 ) (shared.MinerResources, error) {
 	resources := shared.MinerResources{}
+	logger := hclog.FromContext(ctx).With("resourceType", resourceType)
 
 	// Create a temporary dataCache if data is nil
 	if data.resource == "" && (len(data.caches) == 0 || data.caches == nil) {
@@ -106,11 +261,7 @@ func mineResources(
 	}
 
 	for _, cache := range data.caches {
-		if cache.Name == "" {
-			log.Printf("Get %s", data.resource)
-		} else {
-			log.Printf("Get %s: %s", data.resource, cache.Name)
-		}
+		logger.Debug("get resource", "name", cache.Name)
 
 		resourceCrawler, err := utils.NewCrawler(ctx, client, resourceType, crawlerConstructors)
 		if err != nil {
@@ -121,10 +272,28 @@ func mineResources(
 		resource, err := resourceCrawler.Generate(cache)
 		if err != nil {
 			var configErr *utils.MMError
-			if errors.As(err, &configErr) {
-				log.Printf("No properties in resource %s found", resourceType)
+			var propsErr *utils.PropsError
+			if errors.As(err, &configErr) && configErr.Code == utils.Vanished {
+				// Entities deleted after being listed are left out as if never listed
+				vanished.Add(resourceType)
+			} else if errors.As(err, &configErr) {
+				logger.Debug("no properties found", "name", cache.Name)
+			} else if errors.As(err, &propsErr) {
+				// Entities that could not be read are kept, to tell them apart from removed ones
+				logger.Error(
+					"failed to get properties",
+					append(
+						[]any{"resource", propsErr.Identifier, "property", propsErr.PropertyType},
+						utils.ErrorArgs(err)...,
+					)...,
+				)
+				errorResource, err := propsErr.Resource()
+				if err != nil {
+					return shared.MinerResources{}, fmt.Errorf("mineResources: %w", err)
+				}
+				resources = append(resources, errorResource)
 			} else {
-				log.Printf("mineResource: failed to get %s properties: %v", resourceType, err)
+				logger.Error("failed to get properties", utils.ErrorArgs(err)...)
 			}
 		} else {
 			resources = append(resources, resource)
@@ -135,9 +304,9 @@ func mineResources(
 }
 
 func main() {
-	// logger setup for plugin logs
-	log.SetOutput(os.Stderr)
-	log.Printf("Starting miner plugin: %s\n", PLUG_NAME)
+	// logger setup for plugin logs, the level is set from equipment on mining
+	logger := utils.NewLogger(PLUG_NAME, nil)
+	logger.Info("starting miner plugin")
 
 	plugin.Serve(&plugin.ServeConfig{
 		HandshakeConfig: shared.Handshake,
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+// listingActions are the actions listing the cached resource types
+var listingActions = []string{
+	"iam:ListGroups",
+	"iam:ListInstanceProfiles",
+	"iam:ListPolicies",
+	"iam:ListRoles",
+	"iam:ListUsers",
+	"iam:ListVirtualMFADevices",
+}
+
+// cisActions are the actions of the CIS benchmark credential report
+var cisActions = []string{"iam:GenerateCredentialReport", "iam:GetCredentialReport"}
+
+// propertyActions are the actions called by the property crawlers, by property type
+var propertyActions = map[string][]string{
+	userDetail:                    {"iam:GetUser"},
+	userLoginProfile:              {"iam:GetLoginProfile"},
+	userAccessKey:                 {"iam:ListAccessKeys"},
+	userMFADevice:                 {"iam:ListMFADevices", "iam:GetMFADevice"},
+	userSSHPublicKey:              {"iam:ListSSHPublicKeys", "iam:GetSSHPublicKey"},
+	userServiceSpecificCredential: {"iam:ListServiceSpecificCredentials"},
+	userSigningCertificate:        {"iam:ListSigningCertificates"},
+	userGroups:                    {"iam:ListGroupsForUser"},
+	userInlinePolicy:              {"iam:ListUserPolicies", "iam:GetUserPolicy"},
+	userManagedPolicy:             {"iam:ListAttachedUserPolicies"},
+
+	groupDetail:        {"iam:GetGroup"},
+	groupInlinePolicy:  {"iam:ListGroupPolicies", "iam:GetGroupPolicy"},
+	groupManagedPolicy: {"iam:ListAttachedGroupPolicies"},
+
+	policyDetail:   {"iam:GetPolicy"},
+	policyVersions: {"iam:ListPolicyVersions", "iam:GetPolicyVersion"},
+
+	roleDetail:          {"iam:GetRole"},
+	roleInlinePolicy:    {"iam:ListRolePolicies", "iam:GetRolePolicy"},
+	roleManagedPolicy:   {"iam:ListAttachedRolePolicies"},
+	roleInstanceProfile: {"iam:ListInstanceProfilesForRole"},
+	rolePermissionSet:   {},
+
+	accountPasswordPolicy: {"iam:GetAccountPasswordPolicy"},
+	accountSummary:        {"iam:GetAccountSummary"},
+	accountAlias:          {"iam:ListAccountAliases"},
+
+	ssoOIDCProvider: {"iam:ListOpenIDConnectProviders", "iam:GetOpenIDConnectProvider"},
+	ssoSAMLProvider: {"iam:ListSAMLProviders", "iam:GetSAMLProvider"},
+
+	serverCertificateDetail: {"iam:ListServerCertificates", "iam:GetServerCertificate"},
+
+	virtualMFADeviceDetail: {},
+	virtualMFADeviceTags:   {"iam:ListMFADeviceTags"},
+
+	instanceProfileDetail: {"iam:GetInstanceProfile"},
+}
+
+// propsCrawlerConstructors are the property crawlers of each mined resource type
+var propsCrawlerConstructors = map[string][]utils.PropsCrawlerConstructor{
+	iamUser:              userPropsCrawlerConstructors,
+	iamGroup:             groupPropsCrawlerConstructors,
+	iamPolicy:            policyPropsCrawlerConstructors,
+	iamRole:              rolePropsCrawlerConstructors,
+	iamAccount:           accountPropsCrawlerConstructors,
+	iamSSOProviders:      ssoProvidersPropsCrawlerConstructors,
+	iamServerCertificate: serverCertificatePropsCrawlerConstructors,
+	iamVirtualMFADevice:  virtualMFADevicePropsCrawlerConstructors,
+	iamInstanceProfile:   instanceProfilePropsCrawlerConstructors,
+}
+
+// requiredPermissions returns the actions needed to mine the resource types, with the CIS
+// benchmark when enabled
+func requiredPermissions(cisEnabled bool) (utils.Permissions, error) {
+	permissions := utils.Permissions{}
+	permissions.Add(listingActions...)
+	if cisEnabled {
+		permissions.Add(cisActions...)
+	}
+
+	for _, resourceType := range miningResources {
+		constructors, ok := propsCrawlerConstructors[resourceType]
+		if !ok {
+			continue
+		}
+		if err := permissions.AddCrawlers(&iamClient{}, constructors, propertyActions); err != nil {
+			return nil, fmt.Errorf("requiredPermissions(%s): %w", resourceType, err)
+		}
+	}
+
+	return permissions, nil
+}
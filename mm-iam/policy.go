@@ -34,6 +34,8 @@ func (p *policyResource) Generate(datum utils.CacheInfo) (shared.MinerResource,
 
 // policy detail
 type policyDetailMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	configuration *iam.GetPolicyOutput
@@ -98,6 +100,8 @@ func (pd *policyDetailMiner) Generate(datum utils.CacheInfo) ([]shared.MinerProp
 
 // policy versions
 type policyVersionsMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	configuration *iam.GetPolicyVersionOutput
@@ -150,6 +154,13 @@ func (pv *policyVersionsMiner) Generate(datum utils.CacheInfo) ([]shared.MinerPr
 				},
 			)
 			if err != nil {
+				if utils.IsVanished(err) {
+					pv.Logger().Info(
+						"policy version vanished during mining",
+						"versionId", aws.ToString(version.VersionId),
+					)
+					continue
+				}
 				return []shared.MinerProperty{}, fmt.Errorf("generate policyVersions: %w", err)
 			}
 
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
@@ -34,6 +35,8 @@ func (r *roleResource) Generate(datum utils.CacheInfo) (shared.MinerResource, er
 
 // role detail (GetRole)
 type roleDetailMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	configuration *iam.GetRoleOutput
@@ -104,6 +107,8 @@ func (rd *roleDetailMiner) Generate(datum utils.CacheInfo) ([]shared.MinerProper
 
 // role inline policy (GetRolePolicy)
 type roleInlinePolicyMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	paginator     *iam.ListRolePoliciesPaginator
@@ -159,6 +164,10 @@ func (rip *roleInlinePolicyMiner) Generate(datum utils.CacheInfo) ([]shared.Mine
 				},
 			)
 			if err != nil {
+				if utils.IsVanished(err) {
+					rip.Logger().Info("inline policy vanished during mining", "policyName", policyName)
+					continue
+				}
 				return []shared.MinerProperty{}, fmt.Errorf("generate roleInlinePolicy: %w", err)
 			}
 
@@ -193,6 +202,8 @@ func (rip *roleInlinePolicyMiner) Generate(datum utils.CacheInfo) ([]shared.Mine
 
 // role managed policy (ListAttachedRolePolicies)
 type roleManagedPolicyMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	paginator     *iam.ListAttachedRolePoliciesPaginator
@@ -261,6 +272,8 @@ func (rmp *roleManagedPolicyMiner) Generate(datum utils.CacheInfo) ([]shared.Min
 
 // role's instance profile
 type roleInstanceProfileMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	paginator     *iam.ListInstanceProfilesForRolePaginator
@@ -338,3 +351,60 @@ func (rip *roleInstanceProfileMiner) Generate(
 
 	return properties, nil
 }
+
+// Roles provisioned by IAM Identity Center permission sets are named
+// AWSReservedSSO_<PermissionSetName>_<suffix>. The permission set name is recorded
+// so that the role can be cross referenced with mm-identitycenter resources.
+type roleSSOPermissionSetMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *iamClient
+}
+
+func newRoleSSOPermissionSetMiner(serviceClient utils.Client) (*roleSSOPermissionSetMiner, error) {
+	client, err := assertIAMClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newRoleSSOPermissionSetMiner: %v", err)
+	}
+
+	return &roleSSOPermissionSetMiner{
+		propertyType:  rolePermissionSet,
+		serviceClient: client,
+	}, nil
+}
+
+func (rps *roleSSOPermissionSetMiner) PropertyType() string { return rps.propertyType }
+
+func (rps *roleSSOPermissionSetMiner) FetchConf(input any) error {
+	return nil
+}
+
+func (rps *roleSSOPermissionSetMiner) Generate(
+	datum utils.CacheInfo,
+) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	name, found := strings.CutPrefix(datum.Name, reservedSSORolePrefix)
+	separator := strings.LastIndex(name, "_")
+	if !found || separator <= 0 {
+		return nil, &utils.MMError{Category: rolePermissionSet, Code: utils.NoConfig}
+	}
+
+	property := shared.MinerProperty{
+		Type: rolePermissionSet,
+		Label: shared.MinerPropertyLabel{
+			Name:   "PermissionSetName",
+			Unique: true,
+		},
+		Content: shared.MinerPropertyContent{
+			Format: shared.FormatText,
+		},
+	}
+	if err := property.FormatContentValue(name[:separator]); err != nil {
+		return nil, fmt.Errorf("generate rolePermissionSet: %w", err)
+	}
+	properties = append(properties, property)
+
+	return properties, nil
+}
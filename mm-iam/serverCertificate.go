@@ -38,6 +38,8 @@ func (s *serverCertificateResource) Generate(dummy utils.CacheInfo) (shared.Mine
 
 // ServerCertificate detail
 type serverCertificateDetailMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	paginator     *iam.ListServerCertificatesPaginator
@@ -96,6 +98,13 @@ func (sc *serverCertificateDetailMiner) Generate(
 				},
 			)
 			if err != nil {
+				if utils.IsVanished(err) {
+					sc.Logger().Info(
+						"server certificate vanished during mining",
+						"serverCertificateName", aws.ToString(cert.ServerCertificateName),
+					)
+					continue
+				}
 				return []shared.MinerProperty{}, fmt.Errorf("generate serverCertificate: %w", err)
 			}
 
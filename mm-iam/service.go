@@ -9,14 +9,33 @@ import (
 
 type iamClient struct {
 	client *iam.Client
+	// utilization percentage at which account quotas are flagged
+	quotaWarnThreshold float64
+	// partition of the account, for the arns built by the plugin
+	partition utils.Partition
+	// run summary recording the property crawling time
+	run *utils.MiningRun
 }
 
-func newIAMClient(client *iam.Client) *iamClient {
-	return &iamClient{client: client}
+func newIAMClient(
+	client *iam.Client,
+	quotaWarnThreshold float64,
+	partition utils.Partition,
+	run *utils.MiningRun,
+) *iamClient {
+	return &iamClient{
+		client:             client,
+		quotaWarnThreshold: quotaWarnThreshold,
+		partition:          partition,
+		run:                run,
+	}
 }
 
 func (iamc *iamClient) Service() string { return "IAM" }
 
+// Implement the utils.RunRecorder interface
+func (iamc *iamClient) MiningRun() *utils.MiningRun { return iamc.run }
+
 func assertIAMClient(serviceClient utils.Client) (*iamClient, error) {
 	client, ok := serviceClient.(*iamClient)
 	if !ok {
@@ -38,6 +38,8 @@ func (s *ssoProvidersResource) Generate(dummy utils.CacheInfo) (shared.MinerReso
 
 // SSO OpenIDConnect provider
 type ssoOIDCProviderMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	configuration *iam.GetOpenIDConnectProviderOutput
@@ -86,6 +88,10 @@ func (op *ssoOIDCProviderMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerP
 			},
 		)
 		if err != nil {
+			if utils.IsVanished(err) {
+				op.Logger().Info("provider vanished during mining", "arn", aws.ToString(provider.Arn))
+				continue
+			}
 			return []shared.MinerProperty{}, fmt.Errorf("generate SSO OIDC provider: %w", err)
 		}
 
@@ -110,6 +116,8 @@ func (op *ssoOIDCProviderMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerP
 
 // SSO SAML provider
 type ssoSAMLProviderMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	configuration *iam.GetSAMLProviderOutput
@@ -158,6 +166,10 @@ func (sp *ssoSAMLProviderMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerP
 			},
 		)
 		if err != nil {
+			if utils.IsVanished(err) {
+				sp.Logger().Info("provider vanished during mining", "arn", aws.ToString(provider.Arn))
+				continue
+			}
 			return []shared.MinerProperty{}, fmt.Errorf("generate SSO SAML provider: %w", err)
 		}
 
@@ -2,12 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/hashicorp/go-hclog"
 	"github.com/liuminhaw/mist-miner/shared"
 	iamContext "github.com/liuminhaw/mm-plugins/mm-iam/context"
 	"github.com/liuminhaw/mm-plugins/utils"
@@ -25,6 +26,9 @@ type caching struct {
 	roles            dataCache
 	virtualMFAs      dataCache
 	instanceProfiles dataCache
+
+	// failures holds the listing error of each resource type that failed to be read
+	failures map[string]error
 }
 
 func newCaching() *caching {
@@ -35,26 +39,38 @@ func newCaching() *caching {
 		roles:            dataCache{resource: iamRole, caches: []utils.CacheInfo{}},
 		virtualMFAs:      dataCache{resource: iamVirtualMFADevice, caches: []utils.CacheInfo{}},
 		instanceProfiles: dataCache{resource: iamInstanceProfile, caches: []utils.CacheInfo{}},
+		failures:         map[string]error{},
 	}
 }
 
+// read lists every cached resource type. A failed listing does not stop the others, it is
+// recorded in failures and the returned error joins all of them. Entities listed before a
+// listing failed are kept in the cache.
 func (c *caching) read(ctx context.Context, client *iam.Client) error {
-	if err := c.readUsers(client); err != nil {
-		return fmt.Errorf("caching read: %w", err)
-	}
-	if err := c.readGroups(client); err != nil {
-		return fmt.Errorf("caching read: %w", err)
-	}
-	if err := c.readPolicies(ctx, client); err != nil {
-		return fmt.Errorf("caching read: %w", err)
+	readers := []struct {
+		resource string
+		read     func() error
+	}{
+		{iamUser, func() error { return c.readUsers(client) }},
+		{iamGroup, func() error { return c.readGroups(client) }},
+		{iamPolicy, func() error { return c.readPolicies(ctx, client) }},
+		{iamRole, func() error { return c.readRoles(client) }},
+		{iamVirtualMFADevice, func() error { return c.readVirtualMFAs(ctx, client) }},
+		{iamInstanceProfile, func() error { return c.readInstanceProfiles(client) }},
 	}
-	if err := c.readRoles(client); err != nil {
-		return fmt.Errorf("caching read: %w", err)
-	}
-	if err := c.readVirtualMFAs(ctx, client); err != nil {
-		return fmt.Errorf("caching read: %w", err)
+
+	errs := []error{}
+	for _, reader := range readers {
+		if err := reader.read(); err != nil {
+			hclog.FromContext(ctx).Error(
+				"failed to list resource type",
+				append([]any{"resourceType", reader.resource}, utils.ErrorArgs(err)...)...,
+			)
+			c.failures[reader.resource] = err
+			errs = append(errs, err)
+		}
 	}
-	if err := c.readInstanceProfiles(client); err != nil {
+	if err := errors.Join(errs...); err != nil {
 		return fmt.Errorf("caching read: %w", err)
 	}
 
@@ -113,7 +129,7 @@ func (c *caching) readPolicies(ctx context.Context, client *iam.Client) error {
 			AcceptVals: []string{"Local", "AWS", "All"},
 		},
 	)
-	log.Printf("listPoliciesScope: %s\n", listPoliciesScope)
+	hclog.FromContext(ctx).Debug("equipment", "listPoliciesScope", listPoliciesScope)
 
 	input := iam.ListPoliciesInput{Scope: types.PolicyScopeType(listPoliciesScope)}
 	paginator := iam.NewListPoliciesPaginator(client, &input)
@@ -165,7 +181,10 @@ func (c *caching) readVirtualMFAs(ctx context.Context, client *iam.Client) error
 			AcceptVals: []string{"Any", "Assigned", "Unassigned"},
 		},
 	)
-	log.Printf("listVirtualMFAAssignStatus: %s\n", listVirtualMFAAssignStatus)
+	hclog.FromContext(ctx).Debug(
+		"equipment",
+		"listVirtualMFAAssignStatus", listVirtualMFAAssignStatus,
+	)
 
 	input := iam.ListVirtualMFADevicesInput{
 		AssignmentStatus: types.AssignmentStatusType(listVirtualMFAAssignStatus),
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -40,6 +39,8 @@ func (u *userResource) Generate(datum utils.CacheInfo) (shared.MinerResource, er
 
 // user detail (GetUser)
 type userDetailMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	configuration *iam.GetUserOutput
@@ -101,6 +102,8 @@ func (ud *userDetailMiner) Generate(datum utils.CacheInfo) ([]shared.MinerProper
 
 // user login profile (GetLoginProfile)
 type userLoginProfileMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	configuration *iam.GetLoginProfileOutput
@@ -174,6 +177,8 @@ func (ulp *userLoginProfileMiner) Generate(datum utils.CacheInfo) ([]shared.Mine
 
 // user accesskey (NewListAccessKeysPaginator)
 type userAccessKeyMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	paginator     *iam.ListAccessKeysPaginator
@@ -239,6 +244,8 @@ func (uak *userAccessKeyMiner) Generate(datum utils.CacheInfo) ([]shared.MinerPr
 
 // user MFA device (NewListMFADevicesPaginator)
 type userMFADeviceMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	paginator     *iam.ListMFADevicesPaginator
@@ -296,20 +303,30 @@ func (umd *userMFADeviceMiner) Generate(datum utils.CacheInfo) ([]shared.MinerPr
 
 			// Check device type (virtual or hardware)
 			if strings.Contains(aws.ToString(mfaDevice.SerialNumber), "mfa/") {
-				log.Printf(
-					"device: %s, type: virtual MFA Device",
-					aws.ToString(mfaDevice.SerialNumber),
+				umd.Logger().Debug(
+					"virtual MFA device",
+					"serialNumber", aws.ToString(mfaDevice.SerialNumber),
 				)
 				if err = property.FormatContentValue(mfaDevice); err != nil {
 					return []shared.MinerProperty{}, fmt.Errorf("generate user MFADevice: %w", err)
 				}
 			} else {
-				log.Printf("device: %s, type: hardware MFA Device", aws.ToString(mfaDevice.SerialNumber))
+				umd.Logger().Debug(
+					"hardware MFA device",
+					"serialNumber", aws.ToString(mfaDevice.SerialNumber),
+				)
 				device, err := umd.serviceClient.client.GetMFADevice(
 					context.Background(),
 					&iam.GetMFADeviceInput{SerialNumber: mfaDevice.SerialNumber},
 				)
 				if err != nil {
+					if utils.IsVanished(err) {
+						umd.Logger().Info(
+							"MFA device vanished during mining",
+							"serialNumber", aws.ToString(mfaDevice.SerialNumber),
+						)
+						continue
+					}
 					return []shared.MinerProperty{}, fmt.Errorf("generate user MFADevice: %w", err)
 				}
 				property.Label.Name = aws.ToString(device.SerialNumber)
@@ -327,6 +344,8 @@ func (umd *userMFADeviceMiner) Generate(datum utils.CacheInfo) ([]shared.MinerPr
 
 // user SSH public key (NewListSSHPublicKeysPaginator)
 type userSSHPublicKeyMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	paginator     *iam.ListSSHPublicKeysPaginator
@@ -379,6 +398,13 @@ func (uspk *userSSHPublicKeyMiner) Generate(datum utils.CacheInfo) ([]shared.Min
 				},
 			)
 			if err != nil {
+				if utils.IsVanished(err) {
+					uspk.Logger().Info(
+						"SSH public key vanished during mining",
+						"sshPublicKeyId", aws.ToString(keyMetadata.SSHPublicKeyId),
+					)
+					continue
+				}
 				return []shared.MinerProperty{}, fmt.Errorf("generate user SSHPublicKey: %w", err)
 			}
 
@@ -405,6 +431,8 @@ func (uspk *userSSHPublicKeyMiner) Generate(datum utils.CacheInfo) ([]shared.Min
 
 // user Service Specific Credential (ListServiceSpecificCredentials)
 type userServiceSpecificCredentialMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	configuration *iam.ListServiceSpecificCredentialsOutput
@@ -475,6 +503,8 @@ func (ussc *userServiceSpecificCredentialMiner) Generate(
 
 // user signing certificate (NewListSigningCertificatesPaginator)
 type userSigningCertificateMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	paginator     *iam.ListSigningCertificatesPaginator
@@ -552,6 +582,8 @@ func (usc *userSigningCertificateMiner) Generate(
 // user inline policy
 // Including information about the user's inline policies
 type userInlinePolicyMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	paginator     *iam.ListUserPoliciesPaginator
@@ -607,6 +639,10 @@ func (uip *userInlinePolicyMiner) Generate(datum utils.CacheInfo) ([]shared.Mine
 				},
 			)
 			if err != nil {
+				if utils.IsVanished(err) {
+					uip.Logger().Info("inline policy vanished during mining", "policyName", policyName)
+					continue
+				}
 				return []shared.MinerProperty{}, fmt.Errorf("generate user InlinePolicy: %w", err)
 			}
 
@@ -642,6 +678,8 @@ func (uip *userInlinePolicyMiner) Generate(datum utils.CacheInfo) ([]shared.Mine
 // user managed policy
 // Including information about the user's managed policies
 type userManagedPolicyMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	paginator     *iam.ListAttachedUserPoliciesPaginator
@@ -710,6 +748,8 @@ func (ump *userManagedPolicyMiner) Generate(datum utils.CacheInfo) ([]shared.Min
 
 // user belongs groups
 type userGroupsMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	paginator     *iam.ListGroupsForUserPaginator
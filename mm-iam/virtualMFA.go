@@ -39,6 +39,8 @@ func (v *virtualMFADeviceResource) Generate(datum utils.CacheInfo) (shared.Miner
 
 // virtualMFA devices detail
 type virtualMFADeviceDetailMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 }
@@ -86,6 +88,8 @@ func (vmd *virtualMFADeviceDetailMiner) Generate(
 
 // virtualMFA device tags
 type virtualMFADeviceTagsMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *iamClient
 	paginator     *iam.ListMFADeviceTagsPaginator
@@ -0,0 +1,42 @@
+package main
+
+const (
+	// instances
+	instanceDetail = "InstanceDetail"
+
+	// permission sets
+	permissionSetDetail                = "PermissionSetDetail"
+	permissionSetInlinePolicy          = "PermissionSetInlinePolicy"
+	permissionSetManagedPolicy         = "PermissionSetManagedPolicy"
+	permissionSetCustomerManagedPolicy = "PermissionSetCustomerManagedPolicy"
+	permissionSetBoundary              = "PermissionSetPermissionsBoundary"
+	permissionSetAccountAssignment     = "PermissionSetAccountAssignment"
+	permissionSetRole                  = "PermissionSetRole"
+
+	// identity store users
+	userDetail           = "UserDetail"
+	userGroupMemberships = "UserGroupMemberships"
+
+	// identity store groups
+	groupDetail      = "GroupDetail"
+	groupMemberships = "GroupMemberships"
+
+	// crawlers
+	idcInstance      = "Instances"
+	idcPermissionSet = "PermissionSets"
+	idcUser          = "Users"
+	idcGroup         = "Groups"
+
+	// Roles provisioned by permission sets in member accounts are named
+	// AWSReservedSSO_<PermissionSetName>_<suffix>
+	reservedSSORolePrefix = "AWSReservedSSO_"
+
+	valueSeparator = "|"
+)
+
+var miningResources = []string{
+	idcInstance,
+	idcPermissionSet,
+	idcUser,
+	idcGroup,
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/identitystore"
+	"github.com/aws/aws-sdk-go-v2/service/identitystore/types"
+	"github.com/liuminhaw/mist-miner/shared"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+// identityStoreId reads the identity store id from the cached user or group content
+func identityStoreId(datum utils.CacheInfo) (string, error) {
+	var entity struct {
+		IdentityStoreId string
+	}
+	if err := json.Unmarshal([]byte(datum.Content), &entity); err != nil {
+		return "", fmt.Errorf("identityStoreId: %w", err)
+	}
+
+	return entity.IdentityStoreId, nil
+}
+
+type userResource struct {
+	serviceClient *identityCenterClient
+}
+
+func newUserResource(serviceClient utils.Client) (utils.Crawler, error) {
+	client, err := assertIdentityCenterClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newUserResource: %v", err)
+	}
+
+	return &userResource{serviceClient: client}, nil
+}
+
+func (u *userResource) FetchConf(input any) error {
+	return nil
+}
+
+func (u *userResource) Generate(datum utils.CacheInfo) (shared.MinerResource, error) {
+	identifier := fmt.Sprintf("User_%s", datum.Id)
+	return utils.GetProperties(u.serviceClient, identifier, datum, userPropsCrawlerConstructors)
+}
+
+// identity store user detail
+// User detail is cached from ListUsers
+type userDetailMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *identityCenterClient
+}
+
+func newUserDetailMiner(serviceClient utils.Client) (*userDetailMiner, error) {
+	client, err := assertIdentityCenterClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newUserDetailMiner: %v", err)
+	}
+
+	return &userDetailMiner{
+		propertyType:  userDetail,
+		serviceClient: client,
+	}, nil
+}
+
+func (ud *userDetailMiner) PropertyType() string { return ud.propertyType }
+
+func (ud *userDetailMiner) FetchConf(input any) error {
+	return nil
+}
+
+func (ud *userDetailMiner) Generate(datum utils.CacheInfo) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	property := shared.MinerProperty{
+		Type: userDetail,
+		Label: shared.MinerPropertyLabel{
+			Name:   "UserDetail",
+			Unique: true,
+		},
+		Content: shared.MinerPropertyContent{
+			Format: shared.FormatJson,
+			Value:  datum.Content,
+		},
+	}
+	properties = append(properties, property)
+
+	return properties, nil
+}
+
+// identity store user group memberships (ListGroupMembershipsForMember)
+type userGroupMembershipsMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *identityCenterClient
+	paginator     *identitystore.ListGroupMembershipsForMemberPaginator
+}
+
+func newUserGroupMembershipsMiner(serviceClient utils.Client) (*userGroupMembershipsMiner, error) {
+	client, err := assertIdentityCenterClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newUserGroupMembershipsMiner: %v", err)
+	}
+
+	return &userGroupMembershipsMiner{
+		propertyType:  userGroupMemberships,
+		serviceClient: client,
+	}, nil
+}
+
+func (ugm *userGroupMembershipsMiner) PropertyType() string { return ugm.propertyType }
+
+func (ugm *userGroupMembershipsMiner) FetchConf(input any) error {
+	membershipsInput, ok := input.(*identitystore.ListGroupMembershipsForMemberInput)
+	if !ok {
+		return fmt.Errorf("fetchConf: ListGroupMembershipsForMemberInput type assertion failed")
+	}
+
+	ugm.paginator = identitystore.NewListGroupMembershipsForMemberPaginator(
+		ugm.serviceClient.store,
+		membershipsInput,
+	)
+	return nil
+}
+
+func (ugm *userGroupMembershipsMiner) Generate(
+	datum utils.CacheInfo,
+) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	storeId, err := identityStoreId(datum)
+	if err != nil {
+		return nil, fmt.Errorf("generate userGroupMemberships: %w", err)
+	}
+
+	if err := ugm.FetchConf(&identitystore.ListGroupMembershipsForMemberInput{
+		IdentityStoreId: aws.String(storeId),
+		MemberId:        &types.MemberIdMemberUserId{Value: datum.Id},
+	}); err != nil {
+		return nil, fmt.Errorf("generate userGroupMemberships: %w", err)
+	}
+
+	for ugm.paginator.HasMorePages() {
+		page, err := ugm.paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("generate userGroupMemberships: %w", err)
+		}
+
+		for _, membership := range page.GroupMemberships {
+			property := shared.MinerProperty{
+				Type: userGroupMemberships,
+				Label: shared.MinerPropertyLabel{
+					Name:   aws.ToString(membership.GroupId),
+					Unique: true,
+				},
+				Content: shared.MinerPropertyContent{
+					Format: shared.FormatText,
+				},
+			}
+			if err := property.FormatContentValue(aws.ToString(membership.MembershipId)); err != nil {
+				return nil, fmt.Errorf("generate userGroupMemberships: %w", err)
+			}
+			properties = append(properties, property)
+		}
+	}
+
+	return properties, nil
+}
+
+type groupResource struct {
+	serviceClient *identityCenterClient
+}
+
+func newGroupResource(serviceClient utils.Client) (utils.Crawler, error) {
+	client, err := assertIdentityCenterClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newGroupResource: %v", err)
+	}
+
+	return &groupResource{serviceClient: client}, nil
+}
+
+func (g *groupResource) FetchConf(input any) error {
+	return nil
+}
+
+func (g *groupResource) Generate(datum utils.CacheInfo) (shared.MinerResource, error) {
+	identifier := fmt.Sprintf("Group_%s", datum.Id)
+	return utils.GetProperties(g.serviceClient, identifier, datum, groupPropsCrawlerConstructors)
+}
+
+// identity store group detail
+// Group detail is cached from ListGroups
+type groupDetailMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *identityCenterClient
+}
+
+func newGroupDetailMiner(serviceClient utils.Client) (*groupDetailMiner, error) {
+	client, err := assertIdentityCenterClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newGroupDetailMiner: %v", err)
+	}
+
+	return &groupDetailMiner{
+		propertyType:  groupDetail,
+		serviceClient: client,
+	}, nil
+}
+
+func (gd *groupDetailMiner) PropertyType() string { return gd.propertyType }
+
+func (gd *groupDetailMiner) FetchConf(input any) error {
+	return nil
+}
+
+func (gd *groupDetailMiner) Generate(datum utils.CacheInfo) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	property := shared.MinerProperty{
+		Type: groupDetail,
+		Label: shared.MinerPropertyLabel{
+			Name:   "GroupDetail",
+			Unique: true,
+		},
+		Content: shared.MinerPropertyContent{
+			Format: shared.FormatJson,
+			Value:  datum.Content,
+		},
+	}
+	properties = append(properties, property)
+
+	return properties, nil
+}
+
+// identity store group members (ListGroupMemberships)
+type groupMembershipsMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *identityCenterClient
+	paginator     *identitystore.ListGroupMembershipsPaginator
+}
+
+func newGroupMembershipsMiner(serviceClient utils.Client) (*groupMembershipsMiner, error) {
+	client, err := assertIdentityCenterClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newGroupMembershipsMiner: %v", err)
+	}
+
+	return &groupMembershipsMiner{
+		propertyType:  groupMemberships,
+		serviceClient: client,
+	}, nil
+}
+
+func (gm *groupMembershipsMiner) PropertyType() string { return gm.propertyType }
+
+func (gm *groupMembershipsMiner) FetchConf(input any) error {
+	membershipsInput, ok := input.(*identitystore.ListGroupMembershipsInput)
+	if !ok {
+		return fmt.Errorf("fetchConf: ListGroupMembershipsInput type assertion failed")
+	}
+
+	gm.paginator = identitystore.NewListGroupMembershipsPaginator(
+		gm.serviceClient.store,
+		membershipsInput,
+	)
+	return nil
+}
+
+func (gm *groupMembershipsMiner) Generate(datum utils.CacheInfo) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	storeId, err := identityStoreId(datum)
+	if err != nil {
+		return nil, fmt.Errorf("generate groupMemberships: %w", err)
+	}
+
+	if err := gm.FetchConf(&identitystore.ListGroupMembershipsInput{
+		IdentityStoreId: aws.String(storeId),
+		GroupId:         aws.String(datum.Id),
+	}); err != nil {
+		return nil, fmt.Errorf("generate groupMemberships: %w", err)
+	}
+
+	for gm.paginator.HasMorePages() {
+		page, err := gm.paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("generate groupMemberships: %w", err)
+		}
+
+		for _, membership := range page.GroupMemberships {
+			userMember, ok := membership.MemberId.(*types.MemberIdMemberUserId)
+			if !ok {
+				continue
+			}
+
+			property := shared.MinerProperty{
+				Type: groupMemberships,
+				Label: shared.MinerPropertyLabel{
+					Name:   userMember.Value,
+					Unique: true,
+				},
+				Content: shared.MinerPropertyContent{
+					Format: shared.FormatText,
+				},
+			}
+			if err := property.FormatContentValue(aws.ToString(membership.MembershipId)); err != nil {
+				return nil, fmt.Errorf("generate groupMemberships: %w", err)
+			}
+			properties = append(properties, property)
+		}
+	}
+
+	return properties, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/liuminhaw/mist-miner/shared"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+type instanceResource struct {
+	serviceClient *identityCenterClient
+}
+
+func newInstanceResource(serviceClient utils.Client) (utils.Crawler, error) {
+	client, err := assertIdentityCenterClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newInstanceResource: %v", err)
+	}
+
+	return &instanceResource{serviceClient: client}, nil
+}
+
+func (i *instanceResource) FetchConf(input any) error {
+	return nil
+}
+
+func (i *instanceResource) Generate(datum utils.CacheInfo) (shared.MinerResource, error) {
+	identifier := fmt.Sprintf("Instance_%s", datum.Id)
+	return utils.GetProperties(i.serviceClient, identifier, datum, instancePropsCrawlerConstructors)
+}
+
+// instance detail
+// Instance metadata is cached from ListInstances
+type instanceDetailMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *identityCenterClient
+}
+
+func newInstanceDetailMiner(serviceClient utils.Client) (*instanceDetailMiner, error) {
+	client, err := assertIdentityCenterClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newInstanceDetailMiner: %v", err)
+	}
+
+	return &instanceDetailMiner{
+		propertyType:  instanceDetail,
+		serviceClient: client,
+	}, nil
+}
+
+func (id *instanceDetailMiner) PropertyType() string { return id.propertyType }
+
+func (id *instanceDetailMiner) FetchConf(input any) error {
+	return nil
+}
+
+func (id *instanceDetailMiner) Generate(datum utils.CacheInfo) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	property := shared.MinerProperty{
+		Type: instanceDetail,
+		Label: shared.MinerPropertyLabel{
+			Name:   "InstanceDetail",
+			Unique: true,
+		},
+		Content: shared.MinerPropertyContent{
+			Format: shared.FormatJson,
+			Value:  datum.Content,
+		},
+	}
+	properties = append(properties, property)
+
+	return properties, nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+var crawlerConstructors = map[string]utils.CrawlerConstructor{
+	idcInstance: func(ctx context.Context, client utils.Client) (utils.Crawler, error) {
+		return newInstanceResource(client)
+	},
+	idcPermissionSet: func(ctx context.Context, client utils.Client) (utils.Crawler, error) {
+		return newPermissionSetResource(client)
+	},
+	idcUser: func(ctx context.Context, client utils.Client) (utils.Crawler, error) {
+		return newUserResource(client)
+	},
+	idcGroup: func(ctx context.Context, client utils.Client) (utils.Crawler, error) {
+		return newGroupResource(client)
+	},
+}
+
+var instancePropsCrawlerConstructors = []utils.PropsCrawlerConstructor{
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newInstanceDetailMiner(client)
+	},
+}
+
+var permissionSetPropsCrawlerConstructors = []utils.PropsCrawlerConstructor{
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newPermissionSetDetailMiner(client)
+	},
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newPermissionSetInlinePolicyMiner(client)
+	},
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newPermissionSetManagedPolicyMiner(client)
+	},
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newPermissionSetCustomerManagedPolicyMiner(client)
+	},
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newPermissionSetBoundaryMiner(client)
+	},
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newPermissionSetAccountAssignmentMiner(client)
+	},
+}
+
+var userPropsCrawlerConstructors = []utils.PropsCrawlerConstructor{
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newUserDetailMiner(client)
+	},
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newUserGroupMembershipsMiner(client)
+	},
+}
+
+var groupPropsCrawlerConstructors = []utils.PropsCrawlerConstructor{
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newGroupDetailMiner(client)
+	},
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newGroupMembershipsMiner(client)
+	},
+}
@@ -0,0 +1,563 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssoadmin"
+	"github.com/aws/smithy-go"
+	"github.com/liuminhaw/mist-miner/shared"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+type permissionSetResource struct {
+	serviceClient *identityCenterClient
+}
+
+func newPermissionSetResource(serviceClient utils.Client) (utils.Crawler, error) {
+	client, err := assertIdentityCenterClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newPermissionSetResource: %v", err)
+	}
+
+	return &permissionSetResource{serviceClient: client}, nil
+}
+
+func (ps *permissionSetResource) FetchConf(input any) error {
+	return nil
+}
+
+// Generate mines the permission set given by datum, where datum.Name is
+// the permission set arn and datum.Content the arn of its instance.
+func (ps *permissionSetResource) Generate(datum utils.CacheInfo) (shared.MinerResource, error) {
+	identifier := fmt.Sprintf("PermissionSet_%s", datum.Id)
+	return utils.GetProperties(
+		ps.serviceClient,
+		identifier,
+		datum,
+		permissionSetPropsCrawlerConstructors,
+	)
+}
+
+// permission set detail (DescribePermissionSet)
+// Including the name prefix of iam roles provisioned by the permission set
+type permissionSetDetailMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *identityCenterClient
+	configuration *ssoadmin.DescribePermissionSetOutput
+}
+
+func newPermissionSetDetailMiner(serviceClient utils.Client) (*permissionSetDetailMiner, error) {
+	client, err := assertIdentityCenterClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newPermissionSetDetailMiner: %v", err)
+	}
+
+	return &permissionSetDetailMiner{
+		propertyType:  permissionSetDetail,
+		serviceClient: client,
+	}, nil
+}
+
+func (psd *permissionSetDetailMiner) PropertyType() string { return psd.propertyType }
+
+func (psd *permissionSetDetailMiner) FetchConf(input any) error {
+	permissionSetInput, ok := input.(*ssoadmin.DescribePermissionSetInput)
+	if !ok {
+		return fmt.Errorf("fetchConf: DescribePermissionSetInput type assertion failed")
+	}
+
+	var err error
+	psd.configuration, err = psd.serviceClient.admin.DescribePermissionSet(
+		context.Background(),
+		permissionSetInput,
+	)
+	if err != nil {
+		return fmt.Errorf("fetchConf permissionSetDetail: %w", err)
+	}
+
+	return nil
+}
+
+func (psd *permissionSetDetailMiner) Generate(
+	datum utils.CacheInfo,
+) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	if err := psd.FetchConf(&ssoadmin.DescribePermissionSetInput{
+		InstanceArn:      aws.String(datum.Content),
+		PermissionSetArn: aws.String(datum.Name),
+	}); err != nil {
+		return nil, fmt.Errorf("generate permissionSetDetail: %w", err)
+	}
+
+	property := shared.MinerProperty{
+		Type: permissionSetDetail,
+		Label: shared.MinerPropertyLabel{
+			Name:   "PermissionSetDetail",
+			Unique: true,
+		},
+		Content: shared.MinerPropertyContent{
+			Format: shared.FormatJson,
+		},
+	}
+	if err := property.FormatContentValue(psd.configuration.PermissionSet); err != nil {
+		return nil, fmt.Errorf("generate permissionSetDetail: %w", err)
+	}
+	properties = append(properties, property)
+
+	// Roles provisioned into member accounts, as mined by mm-iam, are matched
+	// to the permission set by this name prefix
+	roleProperty := shared.MinerProperty{
+		Type: permissionSetRole,
+		Label: shared.MinerPropertyLabel{
+			Name:   "RoleNamePrefix",
+			Unique: true,
+		},
+		Content: shared.MinerPropertyContent{
+			Format: shared.FormatText,
+		},
+	}
+	roleNamePrefix := fmt.Sprintf(
+		"%s%s_",
+		reservedSSORolePrefix,
+		aws.ToString(psd.configuration.PermissionSet.Name),
+	)
+	if err := roleProperty.FormatContentValue(roleNamePrefix); err != nil {
+		return nil, fmt.Errorf("generate permissionSetDetail: %w", err)
+	}
+	properties = append(properties, roleProperty)
+
+	return properties, nil
+}
+
+// permission set inline policy (GetInlinePolicyForPermissionSet)
+type permissionSetInlinePolicyMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *identityCenterClient
+	configuration *ssoadmin.GetInlinePolicyForPermissionSetOutput
+}
+
+func newPermissionSetInlinePolicyMiner(
+	serviceClient utils.Client,
+) (*permissionSetInlinePolicyMiner, error) {
+	client, err := assertIdentityCenterClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newPermissionSetInlinePolicyMiner: %v", err)
+	}
+
+	return &permissionSetInlinePolicyMiner{
+		propertyType:  permissionSetInlinePolicy,
+		serviceClient: client,
+	}, nil
+}
+
+func (psi *permissionSetInlinePolicyMiner) PropertyType() string { return psi.propertyType }
+
+func (psi *permissionSetInlinePolicyMiner) FetchConf(input any) error {
+	inlinePolicyInput, ok := input.(*ssoadmin.GetInlinePolicyForPermissionSetInput)
+	if !ok {
+		return fmt.Errorf("fetchConf: GetInlinePolicyForPermissionSetInput type assertion failed")
+	}
+
+	var err error
+	psi.configuration, err = psi.serviceClient.admin.GetInlinePolicyForPermissionSet(
+		context.Background(),
+		inlinePolicyInput,
+	)
+	if err != nil {
+		return fmt.Errorf("fetchConf permissionSetInlinePolicy: %w", err)
+	}
+
+	return nil
+}
+
+func (psi *permissionSetInlinePolicyMiner) Generate(
+	datum utils.CacheInfo,
+) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	if err := psi.FetchConf(&ssoadmin.GetInlinePolicyForPermissionSetInput{
+		InstanceArn:      aws.String(datum.Content),
+		PermissionSetArn: aws.String(datum.Name),
+	}); err != nil {
+		return nil, fmt.Errorf("generate permissionSetInlinePolicy: %w", err)
+	}
+
+	if aws.ToString(psi.configuration.InlinePolicy) == "" {
+		return nil, &utils.MMError{Category: permissionSetInlinePolicy, Code: utils.NoConfig}
+	}
+
+	normalizedPolicy, err := shared.JsonNormalize(aws.ToString(psi.configuration.InlinePolicy))
+	if err != nil {
+		return nil, fmt.Errorf("generate permissionSetInlinePolicy: %w", err)
+	}
+	property := shared.MinerProperty{
+		Type: permissionSetInlinePolicy,
+		Label: shared.MinerPropertyLabel{
+			Name:   "InlinePolicy",
+			Unique: true,
+		},
+		Content: shared.MinerPropertyContent{
+			Format: shared.FormatJson,
+			Value:  string(normalizedPolicy),
+		},
+	}
+	properties = append(properties, property)
+
+	return properties, nil
+}
+
+// permission set aws managed policies (ListManagedPoliciesInPermissionSet)
+type permissionSetManagedPolicyMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *identityCenterClient
+	paginator     *ssoadmin.ListManagedPoliciesInPermissionSetPaginator
+}
+
+func newPermissionSetManagedPolicyMiner(
+	serviceClient utils.Client,
+) (*permissionSetManagedPolicyMiner, error) {
+	client, err := assertIdentityCenterClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newPermissionSetManagedPolicyMiner: %v", err)
+	}
+
+	return &permissionSetManagedPolicyMiner{
+		propertyType:  permissionSetManagedPolicy,
+		serviceClient: client,
+	}, nil
+}
+
+func (psm *permissionSetManagedPolicyMiner) PropertyType() string { return psm.propertyType }
+
+func (psm *permissionSetManagedPolicyMiner) FetchConf(input any) error {
+	managedPolicyInput, ok := input.(*ssoadmin.ListManagedPoliciesInPermissionSetInput)
+	if !ok {
+		return fmt.Errorf(
+			"fetchConf: ListManagedPoliciesInPermissionSetInput type assertion failed",
+		)
+	}
+
+	psm.paginator = ssoadmin.NewListManagedPoliciesInPermissionSetPaginator(
+		psm.serviceClient.admin,
+		managedPolicyInput,
+	)
+	return nil
+}
+
+func (psm *permissionSetManagedPolicyMiner) Generate(
+	datum utils.CacheInfo,
+) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	if err := psm.FetchConf(&ssoadmin.ListManagedPoliciesInPermissionSetInput{
+		InstanceArn:      aws.String(datum.Content),
+		PermissionSetArn: aws.String(datum.Name),
+	}); err != nil {
+		return nil, fmt.Errorf("generate permissionSetManagedPolicy: %w", err)
+	}
+
+	for psm.paginator.HasMorePages() {
+		page, err := psm.paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("generate permissionSetManagedPolicy: %w", err)
+		}
+
+		for _, policy := range page.AttachedManagedPolicies {
+			property := shared.MinerProperty{
+				Type: permissionSetManagedPolicy,
+				Label: shared.MinerPropertyLabel{
+					Name:   aws.ToString(policy.Name),
+					Unique: true,
+				},
+				Content: shared.MinerPropertyContent{
+					Format: shared.FormatJson,
+				},
+			}
+			if err := property.FormatContentValue(policy); err != nil {
+				return nil, fmt.Errorf("generate permissionSetManagedPolicy: %w", err)
+			}
+			properties = append(properties, property)
+		}
+	}
+
+	return properties, nil
+}
+
+// permission set customer managed policies (ListCustomerManagedPolicyReferencesInPermissionSet)
+type permissionSetCustomerManagedPolicyMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *identityCenterClient
+	paginator     *ssoadmin.ListCustomerManagedPolicyReferencesInPermissionSetPaginator
+}
+
+func newPermissionSetCustomerManagedPolicyMiner(
+	serviceClient utils.Client,
+) (*permissionSetCustomerManagedPolicyMiner, error) {
+	client, err := assertIdentityCenterClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newPermissionSetCustomerManagedPolicyMiner: %v", err)
+	}
+
+	return &permissionSetCustomerManagedPolicyMiner{
+		propertyType:  permissionSetCustomerManagedPolicy,
+		serviceClient: client,
+	}, nil
+}
+
+func (psc *permissionSetCustomerManagedPolicyMiner) PropertyType() string {
+	return psc.propertyType
+}
+
+func (psc *permissionSetCustomerManagedPolicyMiner) FetchConf(input any) error {
+	policyReferencesInput, ok := input.(*ssoadmin.ListCustomerManagedPolicyReferencesInPermissionSetInput)
+	if !ok {
+		return fmt.Errorf(
+			"fetchConf: ListCustomerManagedPolicyReferencesInPermissionSetInput type assertion failed",
+		)
+	}
+
+	psc.paginator = ssoadmin.NewListCustomerManagedPolicyReferencesInPermissionSetPaginator(
+		psc.serviceClient.admin,
+		policyReferencesInput,
+	)
+	return nil
+}
+
+func (psc *permissionSetCustomerManagedPolicyMiner) Generate(
+	datum utils.CacheInfo,
+) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	if err := psc.FetchConf(&ssoadmin.ListCustomerManagedPolicyReferencesInPermissionSetInput{
+		InstanceArn:      aws.String(datum.Content),
+		PermissionSetArn: aws.String(datum.Name),
+	}); err != nil {
+		return nil, fmt.Errorf("generate permissionSetCustomerManagedPolicy: %w", err)
+	}
+
+	for psc.paginator.HasMorePages() {
+		page, err := psc.paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("generate permissionSetCustomerManagedPolicy: %w", err)
+		}
+
+		for _, reference := range page.CustomerManagedPolicyReferences {
+			property := shared.MinerProperty{
+				Type: permissionSetCustomerManagedPolicy,
+				Label: shared.MinerPropertyLabel{
+					Name:   aws.ToString(reference.Path) + aws.ToString(reference.Name),
+					Unique: true,
+				},
+				Content: shared.MinerPropertyContent{
+					Format: shared.FormatJson,
+				},
+			}
+			if err := property.FormatContentValue(reference); err != nil {
+				return nil, fmt.Errorf("generate permissionSetCustomerManagedPolicy: %w", err)
+			}
+			properties = append(properties, property)
+		}
+	}
+
+	return properties, nil
+}
+
+// permission set permissions boundary (GetPermissionsBoundaryForPermissionSet)
+type permissionSetBoundaryMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *identityCenterClient
+	configuration *ssoadmin.GetPermissionsBoundaryForPermissionSetOutput
+}
+
+func newPermissionSetBoundaryMiner(serviceClient utils.Client) (*permissionSetBoundaryMiner, error) {
+	client, err := assertIdentityCenterClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newPermissionSetBoundaryMiner: %v", err)
+	}
+
+	return &permissionSetBoundaryMiner{
+		propertyType:  permissionSetBoundary,
+		serviceClient: client,
+	}, nil
+}
+
+func (psb *permissionSetBoundaryMiner) PropertyType() string { return psb.propertyType }
+
+func (psb *permissionSetBoundaryMiner) FetchConf(input any) error {
+	boundaryInput, ok := input.(*ssoadmin.GetPermissionsBoundaryForPermissionSetInput)
+	if !ok {
+		return fmt.Errorf(
+			"fetchConf: GetPermissionsBoundaryForPermissionSetInput type assertion failed",
+		)
+	}
+
+	var err error
+	psb.configuration, err = psb.serviceClient.admin.GetPermissionsBoundaryForPermissionSet(
+		context.Background(),
+		boundaryInput,
+	)
+	if err != nil {
+		var apiErr smithy.APIError
+		if ok := errors.As(err, &apiErr); ok {
+			switch apiErr.ErrorCode() {
+			case "ResourceNotFoundException":
+				return &utils.MMError{Category: permissionSetBoundary, Code: utils.NoConfig}
+			default:
+				return fmt.Errorf("fetchConf permissionSetBoundary: %w", err)
+			}
+		}
+		return fmt.Errorf("fetchConf permissionSetBoundary: %w", err)
+	}
+
+	return nil
+}
+
+func (psb *permissionSetBoundaryMiner) Generate(
+	datum utils.CacheInfo,
+) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	if err := psb.FetchConf(&ssoadmin.GetPermissionsBoundaryForPermissionSetInput{
+		InstanceArn:      aws.String(datum.Content),
+		PermissionSetArn: aws.String(datum.Name),
+	}); err != nil {
+		return nil, fmt.Errorf("generate permissionSetBoundary: %w", err)
+	}
+
+	if psb.configuration.PermissionsBoundary != nil {
+		property := shared.MinerProperty{
+			Type: permissionSetBoundary,
+			Label: shared.MinerPropertyLabel{
+				Name:   "PermissionsBoundary",
+				Unique: true,
+			},
+			Content: shared.MinerPropertyContent{
+				Format: shared.FormatJson,
+			},
+		}
+		if err := property.FormatContentValue(psb.configuration.PermissionsBoundary); err != nil {
+			return nil, fmt.Errorf("generate permissionSetBoundary: %w", err)
+		}
+		properties = append(properties, property)
+	}
+
+	return properties, nil
+}
+
+// permission set account assignments
+// (ListAccountsForProvisionedPermissionSet, ListAccountAssignments)
+type permissionSetAccountAssignmentMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *identityCenterClient
+	paginator     *ssoadmin.ListAccountsForProvisionedPermissionSetPaginator
+}
+
+func newPermissionSetAccountAssignmentMiner(
+	serviceClient utils.Client,
+) (*permissionSetAccountAssignmentMiner, error) {
+	client, err := assertIdentityCenterClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newPermissionSetAccountAssignmentMiner: %v", err)
+	}
+
+	return &permissionSetAccountAssignmentMiner{
+		propertyType:  permissionSetAccountAssignment,
+		serviceClient: client,
+	}, nil
+}
+
+func (psa *permissionSetAccountAssignmentMiner) PropertyType() string {
+	return psa.propertyType
+}
+
+func (psa *permissionSetAccountAssignmentMiner) FetchConf(input any) error {
+	provisionedAccountsInput, ok := input.(*ssoadmin.ListAccountsForProvisionedPermissionSetInput)
+	if !ok {
+		return fmt.Errorf(
+			"fetchConf: ListAccountsForProvisionedPermissionSetInput type assertion failed",
+		)
+	}
+
+	psa.paginator = ssoadmin.NewListAccountsForProvisionedPermissionSetPaginator(
+		psa.serviceClient.admin,
+		provisionedAccountsInput,
+	)
+	return nil
+}
+
+func (psa *permissionSetAccountAssignmentMiner) Generate(
+	datum utils.CacheInfo,
+) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	if err := psa.FetchConf(&ssoadmin.ListAccountsForProvisionedPermissionSetInput{
+		InstanceArn:      aws.String(datum.Content),
+		PermissionSetArn: aws.String(datum.Name),
+	}); err != nil {
+		return nil, fmt.Errorf("generate permissionSetAccountAssignment: %w", err)
+	}
+
+	for psa.paginator.HasMorePages() {
+		page, err := psa.paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("generate permissionSetAccountAssignment: %w", err)
+		}
+
+		for _, accountId := range page.AccountIds {
+			assignmentsPaginator := ssoadmin.NewListAccountAssignmentsPaginator(
+				psa.serviceClient.admin,
+				&ssoadmin.ListAccountAssignmentsInput{
+					AccountId:        aws.String(accountId),
+					InstanceArn:      aws.String(datum.Content),
+					PermissionSetArn: aws.String(datum.Name),
+				},
+			)
+			for assignmentsPaginator.HasMorePages() {
+				assignmentsPage, err := assignmentsPaginator.NextPage(context.Background())
+				if err != nil {
+					return nil, fmt.Errorf("generate permissionSetAccountAssignment: %w", err)
+				}
+
+				for _, assignment := range assignmentsPage.AccountAssignments {
+					property := shared.MinerProperty{
+						Type: permissionSetAccountAssignment,
+						Label: shared.MinerPropertyLabel{
+							Name: strings.Join([]string{
+								aws.ToString(assignment.AccountId),
+								string(assignment.PrincipalType),
+								aws.ToString(assignment.PrincipalId),
+							}, valueSeparator),
+							Unique: true,
+						},
+						Content: shared.MinerPropertyContent{
+							Format: shared.FormatJson,
+						},
+					}
+					if err := property.FormatContentValue(assignment); err != nil {
+						return nil, fmt.Errorf("generate permissionSetAccountAssignment: %w", err)
+					}
+					properties = append(properties, property)
+				}
+			}
+		}
+	}
+
+	return properties, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+// listingActions are the actions listing the cached resource types
+var listingActions = []string{
+	"identitystore:ListGroups",
+	"identitystore:ListUsers",
+	"sso:ListInstances",
+	"sso:ListPermissionSets",
+}
+
+// propertyActions are the actions called by the property crawlers, by property type. The
+// iam service prefix of the ssoadmin api is sso.
+var propertyActions = map[string][]string{
+	instanceDetail: {},
+
+	permissionSetDetail:        {"sso:DescribePermissionSet"},
+	permissionSetInlinePolicy:  {"sso:GetInlinePolicyForPermissionSet"},
+	permissionSetManagedPolicy: {"sso:ListManagedPoliciesInPermissionSet"},
+	permissionSetCustomerManagedPolicy: {
+		"sso:ListCustomerManagedPolicyReferencesInPermissionSet",
+	},
+	permissionSetBoundary: {"sso:GetPermissionsBoundaryForPermissionSet"},
+	permissionSetAccountAssignment: {
+		"sso:ListAccountAssignments",
+		"sso:ListAccountsForProvisionedPermissionSet",
+	},
+
+	userDetail:           {},
+	userGroupMemberships: {"identitystore:ListGroupMembershipsForMember"},
+
+	groupDetail:      {},
+	groupMemberships: {"identitystore:ListGroupMemberships"},
+}
+
+// propsCrawlerConstructors are the property crawlers of each mined resource type
+var propsCrawlerConstructors = map[string][]utils.PropsCrawlerConstructor{
+	idcInstance:      instancePropsCrawlerConstructors,
+	idcPermissionSet: permissionSetPropsCrawlerConstructors,
+	idcUser:          userPropsCrawlerConstructors,
+	idcGroup:         groupPropsCrawlerConstructors,
+}
+
+// requiredPermissions returns the actions needed to mine the resource types
+func requiredPermissions() (utils.Permissions, error) {
+	permissions := utils.Permissions{}
+	permissions.Add(listingActions...)
+
+	for _, resourceType := range miningResources {
+		constructors, ok := propsCrawlerConstructors[resourceType]
+		if !ok {
+			continue
+		}
+		err := permissions.AddCrawlers(&identityCenterClient{}, constructors, propertyActions)
+		if err != nil {
+			return nil, fmt.Errorf("requiredPermissions(%s): %w", resourceType, err)
+		}
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/service/identitystore"
+	"github.com/aws/aws-sdk-go-v2/service/ssoadmin"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+type identityCenterClient struct {
+	admin *ssoadmin.Client
+	store *identitystore.Client
+}
+
+func newIdentityCenterClient(
+	admin *ssoadmin.Client,
+	store *identitystore.Client,
+) *identityCenterClient {
+	return &identityCenterClient{admin: admin, store: store}
+}
+
+func (icc *identityCenterClient) Service() string { return "IdentityCenter" }
+
+func assertIdentityCenterClient(serviceClient utils.Client) (*identityCenterClient, error) {
+	client, ok := serviceClient.(*identityCenterClient)
+	if !ok {
+		return nil, errors.New("custom identityCenterClient type assertion failed")
+	}
+
+	return client, nil
+}
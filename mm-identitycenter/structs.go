@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/identitystore"
+	"github.com/aws/aws-sdk-go-v2/service/ssoadmin"
+	"github.com/aws/aws-sdk-go-v2/service/ssoadmin/types"
+	"github.com/liuminhaw/mist-miner/shared"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+type dataCache struct {
+	resource string
+	caches   []utils.CacheInfo
+}
+
+type caching struct {
+	instanceMetadata []types.InstanceMetadata
+
+	instances      dataCache
+	permissionSets dataCache
+	users          dataCache
+	groups         dataCache
+}
+
+func newCaching() *caching {
+	return &caching{
+		instances:      dataCache{resource: idcInstance, caches: []utils.CacheInfo{}},
+		permissionSets: dataCache{resource: idcPermissionSet, caches: []utils.CacheInfo{}},
+		users:          dataCache{resource: idcUser, caches: []utils.CacheInfo{}},
+		groups:         dataCache{resource: idcGroup, caches: []utils.CacheInfo{}},
+	}
+}
+
+func (c *caching) read(ctx context.Context, client *identityCenterClient) error {
+	if err := c.readInstances(client.admin); err != nil {
+		return fmt.Errorf("caching read: %w", err)
+	}
+	if err := c.readPermissionSets(client.admin); err != nil {
+		return fmt.Errorf("caching read: %w", err)
+	}
+	if err := c.readUsers(client.store); err != nil {
+		return fmt.Errorf("caching read: %w", err)
+	}
+	if err := c.readGroups(client.store); err != nil {
+		return fmt.Errorf("caching read: %w", err)
+	}
+
+	return nil
+}
+
+func (c *caching) readInstances(client *ssoadmin.Client) error {
+	paginator := ssoadmin.NewListInstancesPaginator(client, &ssoadmin.ListInstancesInput{})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return fmt.Errorf("caching readInstances: %w", err)
+		}
+
+		for _, instance := range page.Instances {
+			normalizedInstance, err := normalizedContent(instance)
+			if err != nil {
+				return fmt.Errorf("caching readInstances: %w", err)
+			}
+
+			c.instanceMetadata = append(c.instanceMetadata, instance)
+			c.instances.caches = append(c.instances.caches, utils.CacheInfo{
+				Name:    aws.ToString(instance.InstanceArn),
+				Id:      arnResourceId(aws.ToString(instance.InstanceArn)),
+				Content: normalizedInstance,
+			})
+		}
+	}
+
+	return nil
+}
+
+func (c *caching) readPermissionSets(client *ssoadmin.Client) error {
+	for _, instance := range c.instanceMetadata {
+		paginator := ssoadmin.NewListPermissionSetsPaginator(
+			client,
+			&ssoadmin.ListPermissionSetsInput{InstanceArn: instance.InstanceArn},
+		)
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(context.Background())
+			if err != nil {
+				return fmt.Errorf("caching readPermissionSets: %w", err)
+			}
+
+			for _, permissionSetArn := range page.PermissionSets {
+				c.permissionSets.caches = append(c.permissionSets.caches, utils.CacheInfo{
+					Name:    permissionSetArn,
+					Id:      arnResourceId(permissionSetArn),
+					Content: aws.ToString(instance.InstanceArn),
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *caching) readUsers(client *identitystore.Client) error {
+	for _, instance := range c.instanceMetadata {
+		paginator := identitystore.NewListUsersPaginator(
+			client,
+			&identitystore.ListUsersInput{IdentityStoreId: instance.IdentityStoreId},
+		)
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(context.Background())
+			if err != nil {
+				return fmt.Errorf("caching readUsers: %w", err)
+			}
+
+			for _, user := range page.Users {
+				normalizedUser, err := normalizedContent(user)
+				if err != nil {
+					return fmt.Errorf("caching readUsers: %w", err)
+				}
+
+				c.users.caches = append(c.users.caches, utils.CacheInfo{
+					Name:    aws.ToString(user.UserName),
+					Id:      aws.ToString(user.UserId),
+					Content: normalizedUser,
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *caching) readGroups(client *identitystore.Client) error {
+	for _, instance := range c.instanceMetadata {
+		paginator := identitystore.NewListGroupsPaginator(
+			client,
+			&identitystore.ListGroupsInput{IdentityStoreId: instance.IdentityStoreId},
+		)
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(context.Background())
+			if err != nil {
+				return fmt.Errorf("caching readGroups: %w", err)
+			}
+
+			for _, group := range page.Groups {
+				normalizedGroup, err := normalizedContent(group)
+				if err != nil {
+					return fmt.Errorf("caching readGroups: %w", err)
+				}
+
+				c.groups.caches = append(c.groups.caches, utils.CacheInfo{
+					Name:    aws.ToString(group.DisplayName),
+					Id:      aws.ToString(group.GroupId),
+					Content: normalizedGroup,
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+func normalizedContent(data any) (string, error) {
+	marshaledData, err := shared.JsonMarshal(data)
+	if err != nil {
+		return "", fmt.Errorf("normalizedContent: %w", err)
+	}
+	normalizedData, err := shared.JsonNormalize(string(marshaledData))
+	if err != nil {
+		return "", fmt.Errorf("normalizedContent: %w", err)
+	}
+
+	return string(normalizedData), nil
+}
+
+// arnResourceId returns the last path segment of an arn, e.g. ps-1234 from
+// arn:aws:sso:::permissionSet/ssoins-5678/ps-1234
+func arnResourceId(arn string) string {
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
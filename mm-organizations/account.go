@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/liuminhaw/mist-miner/shared"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+type accountResource struct {
+	serviceClient *orgClient
+}
+
+func newAccountResource(serviceClient utils.Client) (utils.Crawler, error) {
+	client, err := assertOrgClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newAccountResource: %v", err)
+	}
+
+	return &accountResource{serviceClient: client}, nil
+}
+
+func (a *accountResource) FetchConf(input any) error {
+	return nil
+}
+
+func (a *accountResource) Generate(datum utils.CacheInfo) (shared.MinerResource, error) {
+	identifier := fmt.Sprintf("Account_%s", datum.Id)
+	return utils.GetProperties(a.serviceClient, identifier, datum, accountPropsCrawlerConstructors)
+}
+
+// account detail (DescribeAccount)
+type accountDetailMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *orgClient
+	configuration *organizations.DescribeAccountOutput
+}
+
+func newAccountDetailMiner(serviceClient utils.Client) (*accountDetailMiner, error) {
+	client, err := assertOrgClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newAccountDetailMiner: %v", err)
+	}
+
+	return &accountDetailMiner{
+		propertyType:  accountDetail,
+		serviceClient: client,
+	}, nil
+}
+
+func (ad *accountDetailMiner) PropertyType() string { return ad.propertyType }
+
+func (ad *accountDetailMiner) FetchConf(input any) error {
+	accountDetailInput, ok := input.(*organizations.DescribeAccountInput)
+	if !ok {
+		return fmt.Errorf("fetchConf: DescribeAccountInput type assertion failed")
+	}
+
+	var err error
+	ad.configuration, err = ad.serviceClient.client.DescribeAccount(
+		context.Background(),
+		accountDetailInput,
+	)
+	if err != nil {
+		return fmt.Errorf("fetchConf accountDetail: %w", err)
+	}
+
+	return nil
+}
+
+func (ad *accountDetailMiner) Generate(datum utils.CacheInfo) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	if err := ad.FetchConf(&organizations.DescribeAccountInput{AccountId: aws.String(datum.Id)}); err != nil {
+		return nil, fmt.Errorf("generate accountDetail: %w", err)
+	}
+
+	property := shared.MinerProperty{
+		Type: accountDetail,
+		Label: shared.MinerPropertyLabel{
+			Name:   "AccountDetail",
+			Unique: true,
+		},
+		Content: shared.MinerPropertyContent{
+			Format: shared.FormatJson,
+		},
+	}
+	if err := property.FormatContentValue(ad.configuration.Account); err != nil {
+		return nil, fmt.Errorf("generate accountDetail: %w", err)
+	}
+	properties = append(properties, property)
+
+	return properties, nil
+}
+
+// account tags (ListTagsForResource)
+type accountTagsMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *orgClient
+	paginator     *organizations.ListTagsForResourcePaginator
+}
+
+func newAccountTagsMiner(serviceClient utils.Client) (*accountTagsMiner, error) {
+	client, err := assertOrgClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newAccountTagsMiner: %v", err)
+	}
+
+	return &accountTagsMiner{
+		propertyType:  accountTags,
+		serviceClient: client,
+	}, nil
+}
+
+func (at *accountTagsMiner) PropertyType() string { return at.propertyType }
+
+func (at *accountTagsMiner) FetchConf(input any) error {
+	tagsInput, ok := input.(*organizations.ListTagsForResourceInput)
+	if !ok {
+		return fmt.Errorf("fetchConf: ListTagsForResourceInput type assertion failed")
+	}
+
+	at.paginator = organizations.NewListTagsForResourcePaginator(at.serviceClient.client, tagsInput)
+	return nil
+}
+
+func (at *accountTagsMiner) Generate(datum utils.CacheInfo) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	if err := at.FetchConf(&organizations.ListTagsForResourceInput{ResourceId: aws.String(datum.Id)}); err != nil {
+		return nil, fmt.Errorf("generate accountTags: %w", err)
+	}
+
+	for at.paginator.HasMorePages() {
+		page, err := at.paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("generate accountTags: %w", err)
+		}
+
+		for _, tag := range page.Tags {
+			property := shared.MinerProperty{
+				Type: accountTags,
+				Label: shared.MinerPropertyLabel{
+					Name:   aws.ToString(tag.Key),
+					Unique: true,
+				},
+				Content: shared.MinerPropertyContent{
+					Format: shared.FormatText,
+				},
+			}
+			if err := property.FormatContentValue(aws.ToString(tag.Value)); err != nil {
+				return nil, fmt.Errorf("generate accountTags: %w", err)
+			}
+			properties = append(properties, property)
+		}
+	}
+
+	return properties, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+
+const (
+	// organization
+	organizationDetail     = "OrganizationDetail"
+	organizationRoot       = "OrganizationRoot"
+	delegatedAdministrator = "DelegatedAdministrator"
+
+	// organizational units
+	ouDetail = "OrganizationalUnitDetail"
+	ouParent = "OrganizationalUnitParent"
+
+	// accounts
+	accountDetail = "AccountDetail"
+	accountParent = "AccountParent"
+	accountTags   = "AccountTags"
+
+	// policies
+	policyDetail  = "PolicyDetail"
+	policyContent = "PolicyContent"
+	policyTarget  = "PolicyTarget"
+
+	// crawlers
+	orgOrganization       = "Organization"
+	orgOrganizationalUnit = "OrganizationalUnits"
+	orgAccount            = "Accounts"
+	orgPolicy             = "Policies"
+
+	// equipments
+	policyEquipmentType = "policies"
+
+	// Resource control policies are not yet defined as enum in the sdk version in use
+	policyTypeResourceControlPolicy types.PolicyType = "RESOURCE_CONTROL_POLICY"
+)
+
+// Property type of the MiningError recorded when a resource type could not be listed
+const listingProperty = "Listing"
+
+var miningResources = []string{
+	orgOrganization,
+	orgOrganizationalUnit,
+	orgAccount,
+	orgPolicy,
+}
+
+var minedPolicyTypes = []types.PolicyType{
+	types.PolicyTypeServiceControlPolicy,
+	policyTypeResourceControlPolicy,
+	types.PolicyTypeTagPolicy,
+	types.PolicyTypeBackupPolicy,
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+var crawlerConstructors = map[string]utils.CrawlerConstructor{
+	orgOrganization: func(ctx context.Context, client utils.Client) (utils.Crawler, error) {
+		return newOrganizationResource(client)
+	},
+	orgOrganizationalUnit: func(ctx context.Context, client utils.Client) (utils.Crawler, error) {
+		return newOrganizationalUnitResource(client)
+	},
+	orgAccount: func(ctx context.Context, client utils.Client) (utils.Crawler, error) {
+		return newAccountResource(client)
+	},
+	orgPolicy: func(ctx context.Context, client utils.Client) (utils.Crawler, error) {
+		return newPolicyResource(client)
+	},
+}
+
+var organizationPropsCrawlerConstructors = []utils.PropsCrawlerConstructor{
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newOrganizationDetailMiner(client)
+	},
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newOrganizationRootMiner(client)
+	},
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newDelegatedAdministratorMiner(client)
+	},
+}
+
+var ouPropsCrawlerConstructors = []utils.PropsCrawlerConstructor{
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newOUDetailMiner(client)
+	},
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newParentMiner(client, ouParent)
+	},
+}
+
+var accountPropsCrawlerConstructors = []utils.PropsCrawlerConstructor{
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newAccountDetailMiner(client)
+	},
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newParentMiner(client, accountParent)
+	},
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newAccountTagsMiner(client)
+	},
+}
+
+var policyPropsCrawlerConstructors = []utils.PropsCrawlerConstructor{
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newPolicyDetailMiner(client)
+	},
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newPolicyTargetMiner(client)
+	},
+}
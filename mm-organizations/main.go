@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+	"github.com/liuminhaw/mist-miner/shared"
+	orgContext "github.com/liuminhaw/mm-plugins/mm-organizations/context"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+var PLUG_NAME = "mm-organizations"
+
+// This is the implementation of Miner
+type Miner struct {
+	resources shared.MinerResources
+}
+
+func (m Miner) Mine(mineConfig shared.MinerConfig) (shared.MinerResources, error) {
+	logger := utils.NewLogger(PLUG_NAME, mineConfig.Equipments)
+	logger.Info("mine")
+
+	// Get authentication profile from config
+	awsAuth, err := utils.ConfigAuth(mineConfig)
+	if err != nil {
+		return nil, fmt.Errorf("mine: %w", err)
+	}
+
+	resources := shared.MinerResources{}
+	cfg, err := config.LoadDefaultConfig(context.Background(),
+		config.WithSharedConfigProfile(string(awsAuth.Profile)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("mine: %w", err)
+	}
+	cfg = utils.LoggerConfig(cfg, logger)
+
+	serviceClient := newOrgClient(organizations.NewFromConfig(cfg))
+
+	ctx := hclog.WithContext(context.Background(), logger)
+	if mineConfig.Equipments != nil {
+		ctx = orgContext.WithEquipments(ctx, mineConfig.Equipments)
+	}
+
+	// Permissions are checked before mining, for the missing ones to be told up front
+	permissionsCheck := utils.NewPermissionsCheck(mineConfig.Equipments)
+	var permissionsResource shared.MinerResource
+	if permissionsCheck.Enabled() {
+		permissions, err := requiredPermissions()
+		if err != nil {
+			return nil, fmt.Errorf("mine: %w", err)
+		}
+		permissionsResource, err = permissionsCheck.Resource(ctx, cfg, permissions, logger)
+		if err != nil {
+			return nil, fmt.Errorf("mine: %w", err)
+		}
+	}
+
+	memory := newCaching()
+
+	// Resource types that failed to be listed are recorded by memory.read and replaced by a
+	// MiningError resource below, the other resource types are still mined
+	_ = memory.read(ctx, serviceClient.client)
+
+	for _, resourceType := range miningResources {
+		logger.Info("mine resource type", "resourceType", resourceType)
+
+		var cachedData dataCache
+		switch resourceType {
+		case orgOrganization:
+			cachedData = dataCache{}
+		case orgOrganizationalUnit:
+			cachedData = memory.organizationalUnits
+		case orgAccount:
+			cachedData = memory.accounts
+		case orgPolicy:
+			cachedData = memory.policies
+		default:
+			logger.Warn("unsupported resource type", "resourceType", resourceType)
+			continue
+		}
+
+		resourcesCrawler, err := mineResources(ctx, serviceClient, resourceType, cachedData)
+		if err != nil {
+			return nil, fmt.Errorf("mine: %w", err)
+		}
+		resources = append(resources, resourcesCrawler...)
+
+		if listErr, ok := memory.failures[resourceType]; ok {
+			listingErr := &utils.PropsError{
+				Identifier:   resourceType,
+				PropertyType: listingProperty,
+				Err:          listErr,
+			}
+			errorResource, err := listingErr.Resource()
+			if err != nil {
+				return nil, fmt.Errorf("mine: %w", err)
+			}
+			resources = append(resources, errorResource)
+		}
+	}
+
+	if permissionsCheck.Enabled() {
+		resources = append(resources, permissionsResource)
+	}
+
+	return resources, nil
+}
+
+func mineResources(
+	ctx context.Context,
+	client utils.Client,
+	resourceType string,
+	data dataCache,
+) (shared.MinerResources, error) {
+	resources := shared.MinerResources{}
+	logger := hclog.FromContext(ctx).With("resourceType", resourceType)
+
+	// Create a temporary dataCache if data is nil
+	if data.resource == "" && (len(data.caches) == 0 || data.caches == nil) {
+		emptyCache := utils.CacheInfo{Name: "", Id: ""}
+		data = dataCache{resource: resourceType, caches: []utils.CacheInfo{emptyCache}}
+	}
+
+	for _, cache := range data.caches {
+		logger.Debug("get resource", "name", cache.Name)
+
+		resourceCrawler, err := utils.NewCrawler(ctx, client, resourceType, crawlerConstructors)
+		if err != nil {
+			return shared.MinerResources{}, fmt.Errorf(
+				"mineResources: failed to create new crawler: %w", err,
+			)
+		}
+		resource, err := resourceCrawler.Generate(cache)
+		if err != nil {
+			var configErr *utils.MMError
+			var propsErr *utils.PropsError
+			if errors.As(err, &configErr) {
+				logger.Debug("no properties found", "name", cache.Name)
+			} else if errors.As(err, &propsErr) {
+				// Resources that could not be fully read are kept, to tell them apart from
+				// removed ones
+				logger.Error(
+					"failed to get properties",
+					append(
+						[]any{"resource", propsErr.Identifier, "property", propsErr.PropertyType},
+						utils.ErrorArgs(err)...,
+					)...,
+				)
+				errorResource, err := propsErr.Resource()
+				if err != nil {
+					return shared.MinerResources{}, fmt.Errorf("mineResources: %w", err)
+				}
+				resources = append(resources, errorResource)
+			} else {
+				logger.Error("failed to get properties", utils.ErrorArgs(err)...)
+			}
+		} else {
+			resource.Sort()
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
+func main() {
+	// logger setup for plugin logs, the level is set from equipment on mining
+	logger := utils.NewLogger(PLUG_NAME, nil)
+	logger.Info("starting miner plugin")
+
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: shared.Handshake,
+		Plugins: map[string]plugin.Plugin{
+			"miner_grpc": &shared.MinerGRPCPlugin{Impl: &Miner{}},
+		},
+		GRPCServer: plugin.DefaultGRPCServer,
+	})
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/aws/smithy-go"
+	"github.com/liuminhaw/mist-miner/shared"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+type organizationResource struct {
+	serviceClient *orgClient
+}
+
+func newOrganizationResource(serviceClient utils.Client) (utils.Crawler, error) {
+	client, err := assertOrgClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newOrganizationResource: %v", err)
+	}
+
+	return &organizationResource{serviceClient: client}, nil
+}
+
+func (o *organizationResource) FetchConf(input any) error {
+	return nil
+}
+
+func (o *organizationResource) Generate(dummy utils.CacheInfo) (shared.MinerResource, error) {
+	return utils.GetProperties(
+		o.serviceClient,
+		"Organization",
+		dummy,
+		organizationPropsCrawlerConstructors,
+	)
+}
+
+// organization detail (DescribeOrganization)
+type organizationDetailMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *orgClient
+	configuration *organizations.DescribeOrganizationOutput
+}
+
+func newOrganizationDetailMiner(serviceClient utils.Client) (*organizationDetailMiner, error) {
+	client, err := assertOrgClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newOrganizationDetailMiner: %v", err)
+	}
+
+	return &organizationDetailMiner{
+		propertyType:  organizationDetail,
+		serviceClient: client,
+	}, nil
+}
+
+func (od *organizationDetailMiner) PropertyType() string { return od.propertyType }
+
+func (od *organizationDetailMiner) FetchConf(input any) error {
+	var err error
+	od.configuration, err = od.serviceClient.client.DescribeOrganization(
+		context.Background(),
+		&organizations.DescribeOrganizationInput{},
+	)
+	if err != nil {
+		return fmt.Errorf("fetchConf organizationDetail: %w", err)
+	}
+
+	return nil
+}
+
+func (od *organizationDetailMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	if err := od.FetchConf(nil); err != nil {
+		return nil, fmt.Errorf("generate organizationDetail: %w", err)
+	}
+
+	property := shared.MinerProperty{
+		Type: organizationDetail,
+		Label: shared.MinerPropertyLabel{
+			Name:   "OrganizationDetail",
+			Unique: true,
+		},
+		Content: shared.MinerPropertyContent{
+			Format: shared.FormatJson,
+		},
+	}
+	if err := property.FormatContentValue(od.configuration.Organization); err != nil {
+		return nil, fmt.Errorf("generate organizationDetail: %w", err)
+	}
+	properties = append(properties, property)
+
+	return properties, nil
+}
+
+// organization roots (ListRoots)
+// Including the policy types enabled in each root
+type organizationRootMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *orgClient
+	paginator     *organizations.ListRootsPaginator
+}
+
+func newOrganizationRootMiner(serviceClient utils.Client) (*organizationRootMiner, error) {
+	client, err := assertOrgClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newOrganizationRootMiner: %v", err)
+	}
+
+	return &organizationRootMiner{
+		propertyType:  organizationRoot,
+		serviceClient: client,
+	}, nil
+}
+
+func (rt *organizationRootMiner) PropertyType() string { return rt.propertyType }
+
+func (rt *organizationRootMiner) FetchConf(input any) error {
+	listRootsInput, ok := input.(*organizations.ListRootsInput)
+	if !ok {
+		return fmt.Errorf("fetchConf: ListRootsInput type assertion failed")
+	}
+
+	rt.paginator = organizations.NewListRootsPaginator(rt.serviceClient.client, listRootsInput)
+	return nil
+}
+
+func (rt *organizationRootMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	if err := rt.FetchConf(&organizations.ListRootsInput{}); err != nil {
+		return nil, fmt.Errorf("generate organizationRoot: %w", err)
+	}
+
+	for rt.paginator.HasMorePages() {
+		page, err := rt.paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("generate organizationRoot: %w", err)
+		}
+
+		for _, root := range page.Roots {
+			property := shared.MinerProperty{
+				Type: organizationRoot,
+				Label: shared.MinerPropertyLabel{
+					Name:   aws.ToString(root.Id),
+					Unique: true,
+				},
+				Content: shared.MinerPropertyContent{
+					Format: shared.FormatJson,
+				},
+			}
+			if err := property.FormatContentValue(root); err != nil {
+				return nil, fmt.Errorf("generate organizationRoot: %w", err)
+			}
+			properties = append(properties, property)
+		}
+	}
+
+	return properties, nil
+}
+
+// delegated administrators (ListDelegatedAdministrators)
+// Including the services each administrator account is delegated for
+type delegatedAdministratorMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *orgClient
+	paginator     *organizations.ListDelegatedAdministratorsPaginator
+}
+
+func newDelegatedAdministratorMiner(
+	serviceClient utils.Client,
+) (*delegatedAdministratorMiner, error) {
+	client, err := assertOrgClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newDelegatedAdministratorMiner: %v", err)
+	}
+
+	return &delegatedAdministratorMiner{
+		propertyType:  delegatedAdministrator,
+		serviceClient: client,
+	}, nil
+}
+
+func (da *delegatedAdministratorMiner) PropertyType() string { return da.propertyType }
+
+func (da *delegatedAdministratorMiner) FetchConf(input any) error {
+	delegatedAdminInput, ok := input.(*organizations.ListDelegatedAdministratorsInput)
+	if !ok {
+		return fmt.Errorf("fetchConf: ListDelegatedAdministratorsInput type assertion failed")
+	}
+
+	da.paginator = organizations.NewListDelegatedAdministratorsPaginator(
+		da.serviceClient.client,
+		delegatedAdminInput,
+	)
+	return nil
+}
+
+func (da *delegatedAdministratorMiner) Generate(
+	dummy utils.CacheInfo,
+) ([]shared.MinerProperty, error) {
+	type delegatedAdministratorInfo struct {
+		Administrator types.DelegatedAdministrator `json:"administrator"`
+		Services      []types.DelegatedService     `json:"services"`
+	}
+
+	properties := []shared.MinerProperty{}
+
+	if err := da.FetchConf(&organizations.ListDelegatedAdministratorsInput{}); err != nil {
+		return nil, fmt.Errorf("generate delegatedAdministrator: %w", err)
+	}
+
+	for da.paginator.HasMorePages() {
+		page, err := da.paginator.NextPage(context.Background())
+		if err != nil {
+			// Delegated administrators are only listed from the management account or a
+			// delegated administrator account, other accounts have none to tell
+			var apiErr smithy.APIError
+			if errors.As(err, &apiErr) {
+				switch apiErr.ErrorCode() {
+				case "AccessDeniedException", "AWSOrganizationsNotInUseException":
+					return nil, &utils.MMError{Category: delegatedAdministrator, Code: utils.NoConfig}
+				}
+			}
+			return nil, fmt.Errorf("generate delegatedAdministrator: %w", err)
+		}
+
+		for _, admin := range page.DelegatedAdministrators {
+			services := []types.DelegatedService{}
+			servicesPaginator := organizations.NewListDelegatedServicesForAccountPaginator(
+				da.serviceClient.client,
+				&organizations.ListDelegatedServicesForAccountInput{AccountId: admin.Id},
+			)
+			for servicesPaginator.HasMorePages() {
+				servicesPage, err := servicesPaginator.NextPage(context.Background())
+				if err != nil {
+					return nil, fmt.Errorf("generate delegatedAdministrator: %w", err)
+				}
+				services = append(services, servicesPage.DelegatedServices...)
+			}
+
+			property := shared.MinerProperty{
+				Type: delegatedAdministrator,
+				Label: shared.MinerPropertyLabel{
+					Name:   aws.ToString(admin.Id),
+					Unique: true,
+				},
+				Content: shared.MinerPropertyContent{
+					Format: shared.FormatJson,
+				},
+			}
+			if err := property.FormatContentValue(delegatedAdministratorInfo{
+				Administrator: admin,
+				Services:      services,
+			}); err != nil {
+				return nil, fmt.Errorf("generate delegatedAdministrator: %w", err)
+			}
+			properties = append(properties, property)
+		}
+	}
+
+	return properties, nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/liuminhaw/mist-miner/shared"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+type organizationalUnitResource struct {
+	serviceClient *orgClient
+}
+
+func newOrganizationalUnitResource(serviceClient utils.Client) (utils.Crawler, error) {
+	client, err := assertOrgClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newOrganizationalUnitResource: %v", err)
+	}
+
+	return &organizationalUnitResource{serviceClient: client}, nil
+}
+
+func (ou *organizationalUnitResource) FetchConf(input any) error {
+	return nil
+}
+
+func (ou *organizationalUnitResource) Generate(datum utils.CacheInfo) (shared.MinerResource, error) {
+	identifier := fmt.Sprintf("OU_%s", datum.Id)
+	return utils.GetProperties(ou.serviceClient, identifier, datum, ouPropsCrawlerConstructors)
+}
+
+// organizational unit detail (DescribeOrganizationalUnit)
+type ouDetailMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *orgClient
+	configuration *organizations.DescribeOrganizationalUnitOutput
+}
+
+func newOUDetailMiner(serviceClient utils.Client) (*ouDetailMiner, error) {
+	client, err := assertOrgClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newOUDetailMiner: %v", err)
+	}
+
+	return &ouDetailMiner{
+		propertyType:  ouDetail,
+		serviceClient: client,
+	}, nil
+}
+
+func (od *ouDetailMiner) PropertyType() string { return od.propertyType }
+
+func (od *ouDetailMiner) FetchConf(input any) error {
+	ouDetailInput, ok := input.(*organizations.DescribeOrganizationalUnitInput)
+	if !ok {
+		return fmt.Errorf("fetchConf: DescribeOrganizationalUnitInput type assertion failed")
+	}
+
+	var err error
+	od.configuration, err = od.serviceClient.client.DescribeOrganizationalUnit(
+		context.Background(),
+		ouDetailInput,
+	)
+	if err != nil {
+		return fmt.Errorf("fetchConf ouDetail: %w", err)
+	}
+
+	return nil
+}
+
+func (od *ouDetailMiner) Generate(datum utils.CacheInfo) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	err := od.FetchConf(
+		&organizations.DescribeOrganizationalUnitInput{OrganizationalUnitId: aws.String(datum.Id)},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("generate ouDetail: %w", err)
+	}
+
+	property := shared.MinerProperty{
+		Type: ouDetail,
+		Label: shared.MinerPropertyLabel{
+			Name:   "OrganizationalUnitDetail",
+			Unique: true,
+		},
+		Content: shared.MinerPropertyContent{
+			Format: shared.FormatJson,
+		},
+	}
+	if err := property.FormatContentValue(od.configuration.OrganizationalUnit); err != nil {
+		return nil, fmt.Errorf("generate ouDetail: %w", err)
+	}
+	properties = append(properties, property)
+
+	return properties, nil
+}
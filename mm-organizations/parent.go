@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/liuminhaw/mist-miner/shared"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+// parent of organizational unit or account (ListParents)
+type parentMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *orgClient
+	paginator     *organizations.ListParentsPaginator
+}
+
+func newParentMiner(serviceClient utils.Client, property string) (*parentMiner, error) {
+	client, err := assertOrgClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newParentMiner: %v", err)
+	}
+
+	return &parentMiner{propertyType: property, serviceClient: client}, nil
+}
+
+func (p *parentMiner) PropertyType() string { return p.propertyType }
+
+func (p *parentMiner) FetchConf(input any) error {
+	listParentsInput, ok := input.(*organizations.ListParentsInput)
+	if !ok {
+		return fmt.Errorf("fetchConf: ListParentsInput type assertion failed")
+	}
+
+	p.paginator = organizations.NewListParentsPaginator(p.serviceClient.client, listParentsInput)
+	return nil
+}
+
+func (p *parentMiner) Generate(datum utils.CacheInfo) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	if err := p.FetchConf(&organizations.ListParentsInput{ChildId: aws.String(datum.Id)}); err != nil {
+		return nil, fmt.Errorf("generate %s: %w", p.propertyType, err)
+	}
+
+	for p.paginator.HasMorePages() {
+		page, err := p.paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("generate %s: %w", p.propertyType, err)
+		}
+
+		for _, parent := range page.Parents {
+			property := shared.MinerProperty{
+				Type: p.propertyType,
+				Label: shared.MinerPropertyLabel{
+					Name:   "Parent",
+					Unique: true,
+				},
+				Content: shared.MinerPropertyContent{
+					Format: shared.FormatJson,
+				},
+			}
+			if err := property.FormatContentValue(parent); err != nil {
+				return nil, fmt.Errorf("generate %s: %w", p.propertyType, err)
+			}
+			properties = append(properties, property)
+		}
+	}
+
+	return properties, nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+// listingActions are the actions listing the cached resource types
+var listingActions = []string{
+	"organizations:ListAccounts",
+	"organizations:ListOrganizationalUnitsForParent",
+	"organizations:ListPolicies",
+	"organizations:ListRoots",
+}
+
+// propertyActions are the actions called by the property crawlers, by property type
+var propertyActions = map[string][]string{
+	organizationDetail: {"organizations:DescribeOrganization"},
+	organizationRoot:   {"organizations:ListRoots"},
+	delegatedAdministrator: {
+		"organizations:ListDelegatedAdministrators",
+		"organizations:ListDelegatedServicesForAccount",
+	},
+
+	ouDetail: {"organizations:DescribeOrganizationalUnit"},
+	ouParent: {"organizations:ListParents"},
+
+	accountDetail: {"organizations:DescribeAccount"},
+	accountParent: {"organizations:ListParents"},
+	accountTags:   {"organizations:ListTagsForResource"},
+
+	policyDetail: {"organizations:DescribePolicy"},
+	policyTarget: {"organizations:ListTargetsForPolicy"},
+}
+
+// propsCrawlerConstructors are the property crawlers of each mined resource type
+var propsCrawlerConstructors = map[string][]utils.PropsCrawlerConstructor{
+	orgOrganization:       organizationPropsCrawlerConstructors,
+	orgOrganizationalUnit: ouPropsCrawlerConstructors,
+	orgAccount:            accountPropsCrawlerConstructors,
+	orgPolicy:             policyPropsCrawlerConstructors,
+}
+
+// requiredPermissions returns the actions needed to mine the resource types
+func requiredPermissions() (utils.Permissions, error) {
+	permissions := utils.Permissions{}
+	permissions.Add(listingActions...)
+
+	for _, resourceType := range miningResources {
+		constructors, ok := propsCrawlerConstructors[resourceType]
+		if !ok {
+			continue
+		}
+		if err := permissions.AddCrawlers(&orgClient{}, constructors, propertyActions); err != nil {
+			return nil, fmt.Errorf("requiredPermissions(%s): %w", resourceType, err)
+		}
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/liuminhaw/mist-miner/shared"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+type policyResource struct {
+	serviceClient *orgClient
+}
+
+func newPolicyResource(serviceClient utils.Client) (utils.Crawler, error) {
+	client, err := assertOrgClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newPolicyResource: %v", err)
+	}
+
+	return &policyResource{serviceClient: client}, nil
+}
+
+func (p *policyResource) FetchConf(input any) error {
+	return nil
+}
+
+func (p *policyResource) Generate(datum utils.CacheInfo) (shared.MinerResource, error) {
+	identifier := fmt.Sprintf("Policy_%s", datum.Id)
+	return utils.GetProperties(p.serviceClient, identifier, datum, policyPropsCrawlerConstructors)
+}
+
+// policy detail and decoded document (DescribePolicy)
+type policyDetailMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *orgClient
+	configuration *organizations.DescribePolicyOutput
+}
+
+func newPolicyDetailMiner(serviceClient utils.Client) (*policyDetailMiner, error) {
+	client, err := assertOrgClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newPolicyDetailMiner: %v", err)
+	}
+
+	return &policyDetailMiner{
+		propertyType:  policyDetail,
+		serviceClient: client,
+	}, nil
+}
+
+func (pd *policyDetailMiner) PropertyType() string { return pd.propertyType }
+
+func (pd *policyDetailMiner) FetchConf(input any) error {
+	policyInput, ok := input.(*organizations.DescribePolicyInput)
+	if !ok {
+		return fmt.Errorf("fetchConf: DescribePolicyInput type assertion failed")
+	}
+
+	var err error
+	pd.configuration, err = pd.serviceClient.client.DescribePolicy(context.Background(), policyInput)
+	if err != nil {
+		return fmt.Errorf("fetchConf policyDetail: %w", err)
+	}
+
+	return nil
+}
+
+func (pd *policyDetailMiner) Generate(datum utils.CacheInfo) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	if err := pd.FetchConf(&organizations.DescribePolicyInput{PolicyId: aws.String(datum.Id)}); err != nil {
+		return nil, fmt.Errorf("generate policyDetail: %w", err)
+	}
+
+	property := shared.MinerProperty{
+		Type: policyDetail,
+		Label: shared.MinerPropertyLabel{
+			Name:   "PolicyDetail",
+			Unique: true,
+		},
+		Content: shared.MinerPropertyContent{
+			Format: shared.FormatJson,
+		},
+	}
+	if err := property.FormatContentValue(pd.configuration.Policy.PolicySummary); err != nil {
+		return nil, fmt.Errorf("generate policyDetail: %w", err)
+	}
+	properties = append(properties, property)
+
+	// Policy content is a json document in string form
+	normalizedContent, err := shared.JsonNormalize(aws.ToString(pd.configuration.Policy.Content))
+	if err != nil {
+		return nil, fmt.Errorf("generate policyDetail: %w", err)
+	}
+	properties = append(properties, shared.MinerProperty{
+		Type: policyContent,
+		Label: shared.MinerPropertyLabel{
+			Name:   "PolicyContent",
+			Unique: true,
+		},
+		Content: shared.MinerPropertyContent{
+			Format: shared.FormatJson,
+			Value:  string(normalizedContent),
+		},
+	})
+
+	return properties, nil
+}
+
+// policy attachments (ListTargetsForPolicy)
+type policyTargetMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *orgClient
+	paginator     *organizations.ListTargetsForPolicyPaginator
+}
+
+func newPolicyTargetMiner(serviceClient utils.Client) (*policyTargetMiner, error) {
+	client, err := assertOrgClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newPolicyTargetMiner: %v", err)
+	}
+
+	return &policyTargetMiner{
+		propertyType:  policyTarget,
+		serviceClient: client,
+	}, nil
+}
+
+func (pt *policyTargetMiner) PropertyType() string { return pt.propertyType }
+
+func (pt *policyTargetMiner) FetchConf(input any) error {
+	targetsInput, ok := input.(*organizations.ListTargetsForPolicyInput)
+	if !ok {
+		return fmt.Errorf("fetchConf: ListTargetsForPolicyInput type assertion failed")
+	}
+
+	pt.paginator = organizations.NewListTargetsForPolicyPaginator(
+		pt.serviceClient.client,
+		targetsInput,
+	)
+	return nil
+}
+
+func (pt *policyTargetMiner) Generate(datum utils.CacheInfo) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	if err := pt.FetchConf(&organizations.ListTargetsForPolicyInput{PolicyId: aws.String(datum.Id)}); err != nil {
+		return nil, fmt.Errorf("generate policyTarget: %w", err)
+	}
+
+	for pt.paginator.HasMorePages() {
+		page, err := pt.paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("generate policyTarget: %w", err)
+		}
+
+		for _, target := range page.Targets {
+			property := shared.MinerProperty{
+				Type: policyTarget,
+				Label: shared.MinerPropertyLabel{
+					Name:   aws.ToString(target.TargetId),
+					Unique: true,
+				},
+				Content: shared.MinerPropertyContent{
+					Format: shared.FormatJson,
+				},
+			}
+			if err := property.FormatContentValue(target); err != nil {
+				return nil, fmt.Errorf("generate policyTarget: %w", err)
+			}
+			properties = append(properties, property)
+		}
+	}
+
+	return properties, nil
+}
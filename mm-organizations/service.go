@@ -0,0 +1,27 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+type orgClient struct {
+	client *organizations.Client
+}
+
+func newOrgClient(client *organizations.Client) *orgClient {
+	return &orgClient{client: client}
+}
+
+func (orgc *orgClient) Service() string { return "Organizations" }
+
+func assertOrgClient(serviceClient utils.Client) (*orgClient, error) {
+	client, ok := serviceClient.(*orgClient)
+	if !ok {
+		return nil, errors.New("custom orgClient type assertion failed")
+	}
+
+	return client, nil
+}
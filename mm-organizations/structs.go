@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/hashicorp/go-hclog"
+	orgContext "github.com/liuminhaw/mm-plugins/mm-organizations/context"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+type dataCache struct {
+	resource string
+	caches   []utils.CacheInfo
+}
+
+type caching struct {
+	organizationalUnits dataCache
+	accounts            dataCache
+	policies            dataCache
+
+	// failures holds the listing error of each resource type that failed to be read
+	failures map[string]error
+}
+
+func newCaching() *caching {
+	return &caching{
+		organizationalUnits: dataCache{resource: orgOrganizationalUnit, caches: []utils.CacheInfo{}},
+		accounts:            dataCache{resource: orgAccount, caches: []utils.CacheInfo{}},
+		policies:            dataCache{resource: orgPolicy, caches: []utils.CacheInfo{}},
+		failures:            map[string]error{},
+	}
+}
+
+// read lists every cached resource type. A failed listing does not stop the others, it is
+// recorded in failures and the returned error joins all of them.
+func (c *caching) read(ctx context.Context, client *organizations.Client) error {
+	readers := []struct {
+		resource string
+		read     func() error
+	}{
+		{orgOrganizationalUnit, func() error { return c.readOrganizationalUnits(client) }},
+		{orgAccount, func() error { return c.readAccounts(client) }},
+		{orgPolicy, func() error { return c.readPolicies(ctx, client) }},
+	}
+
+	errs := []error{}
+	for _, reader := range readers {
+		if err := reader.read(); err != nil {
+			hclog.FromContext(ctx).Error(
+				"failed to list resource type",
+				append([]any{"resourceType", reader.resource}, utils.ErrorArgs(err)...)...,
+			)
+			c.failures[reader.resource] = err
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("caching read: %w", err)
+	}
+
+	return nil
+}
+
+// readOrganizationalUnits walks the organization tree from its roots
+// and caches every organizational unit found.
+func (c *caching) readOrganizationalUnits(client *organizations.Client) error {
+	parents := []string{}
+
+	rootsPaginator := organizations.NewListRootsPaginator(client, &organizations.ListRootsInput{})
+	for rootsPaginator.HasMorePages() {
+		page, err := rootsPaginator.NextPage(context.Background())
+		if err != nil {
+			return fmt.Errorf("caching readOrganizationalUnits: %w", err)
+		}
+
+		for _, root := range page.Roots {
+			parents = append(parents, aws.ToString(root.Id))
+		}
+	}
+
+	for len(parents) > 0 {
+		parentId := parents[0]
+		parents = parents[1:]
+
+		paginator := organizations.NewListOrganizationalUnitsForParentPaginator(
+			client,
+			&organizations.ListOrganizationalUnitsForParentInput{ParentId: aws.String(parentId)},
+		)
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(context.Background())
+			if err != nil {
+				return fmt.Errorf("caching readOrganizationalUnits: %w", err)
+			}
+
+			for _, ou := range page.OrganizationalUnits {
+				c.organizationalUnits.caches = append(c.organizationalUnits.caches, utils.CacheInfo{
+					Name: aws.ToString(ou.Name),
+					Id:   aws.ToString(ou.Id),
+				})
+				parents = append(parents, aws.ToString(ou.Id))
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *caching) readAccounts(client *organizations.Client) error {
+	paginator := organizations.NewListAccountsPaginator(client, &organizations.ListAccountsInput{})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return fmt.Errorf("caching readAccounts: %w", err)
+		}
+
+		for _, account := range page.Accounts {
+			c.accounts.caches = append(c.accounts.caches, utils.CacheInfo{
+				Name: aws.ToString(account.Name),
+				Id:   aws.ToString(account.Id),
+			})
+		}
+	}
+
+	return nil
+}
+
+func (c *caching) readPolicies(ctx context.Context, client *organizations.Client) error {
+	listPoliciesFilter := utils.GetEquipAttribute(
+		orgContext.Equipments(ctx),
+		utils.EquipmentInfo{
+			TargetType: policyEquipmentType,
+			TargetName: "list",
+			TargetAttr: "filter",
+			DefaultVal: "All",
+			AcceptVals: []string{
+				"All",
+				string(types.PolicyTypeServiceControlPolicy),
+				string(policyTypeResourceControlPolicy),
+				string(types.PolicyTypeTagPolicy),
+				string(types.PolicyTypeBackupPolicy),
+			},
+		},
+	)
+	hclog.FromContext(ctx).Debug("equipment", "listPoliciesFilter", listPoliciesFilter)
+
+	policyTypes := minedPolicyTypes
+	if listPoliciesFilter != "All" {
+		policyTypes = []types.PolicyType{types.PolicyType(listPoliciesFilter)}
+	}
+
+	for _, policyType := range policyTypes {
+		paginator := organizations.NewListPoliciesPaginator(
+			client,
+			&organizations.ListPoliciesInput{Filter: policyType},
+		)
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(context.Background())
+			if err != nil {
+				return fmt.Errorf("caching readPolicies %s: %w", policyType, err)
+			}
+
+			for _, policy := range page.Policies {
+				c.policies.caches = append(c.policies.caches, utils.CacheInfo{
+					Name:    aws.ToString(policy.Name),
+					Id:      aws.ToString(policy.Id),
+					Content: string(policy.Type),
+				})
+			}
+		}
+	}
+
+	return nil
+}
@@ -10,6 +10,8 @@ import (
 )
 
 type accelerateMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *s3Client
 	configuration *s3.GetBucketAccelerateConfigurationOutput
@@ -61,7 +63,7 @@ func (a *accelerateMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerPropert
 			Format: shared.FormatJson,
 		},
 	}
-	if err := property.FormatContentValue(a.configuration); err != nil {
+	if err := property.FormatContentValue(utils.CanonicalContent(a.configuration)); err != nil {
 		return nil, fmt.Errorf("generate accelerate configuration: %w", err)
 	}
 	properties = append(properties, property)
@@ -0,0 +1,559 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3control"
+	"github.com/aws/aws-sdk-go-v2/service/s3control/types"
+	"github.com/aws/smithy-go"
+	"github.com/liuminhaw/mist-miner/shared"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+// accessPointMiner gets the access points of the bucket in the account, with their
+// network origin, block public access settings, policy and policy status. Failed lookups,
+// e.g. access denied on s3control, are recorded in the access point content instead of
+// failing the bucket.
+type accessPointMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *s3Client
+	paginator     *s3control.ListAccessPointsPaginator
+}
+
+// accessPointDetail is the content of an AccessPoint property
+type accessPointDetail struct {
+	Name             *string
+	Alias            *string
+	AccessPointArn   *string
+	Bucket           *string
+	BucketAccountId  *string
+	CreationDate     *time.Time
+	Endpoints        map[string]string
+	NetworkOrigin    types.NetworkOrigin
+	VpcConfiguration *types.VpcConfiguration
+	// Errors of the lookups that could not be made, as <operation>: <error code>
+	Errors []string
+}
+
+func newAccessPointMiner(serviceClient utils.Client, property string) (*accessPointMiner, error) {
+	client, err := assertS3Client(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newAccessPointMiner: %w", err)
+	}
+
+	return &accessPointMiner{propertyType: property, serviceClient: client}, nil
+}
+
+func (ap *accessPointMiner) PropertyType() string { return ap.propertyType }
+
+func (ap *accessPointMiner) FetchConf(input any) error {
+	accessPointsInput, ok := input.(*s3control.ListAccessPointsInput)
+	if !ok {
+		return fmt.Errorf("fetchConf: ListAccessPointsInput type assertion failed")
+	}
+
+	ap.paginator = s3control.NewListAccessPointsPaginator(
+		ap.serviceClient.control,
+		accessPointsInput,
+	)
+	return nil
+}
+
+func (ap *accessPointMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	// Account id is required by s3control, it is not available when sts failed
+	if ap.serviceClient.accountId == "" {
+		return nil, &utils.MMError{Category: accessPoint, Code: utils.NoConfig}
+	}
+
+	if err := ap.FetchConf(&s3control.ListAccessPointsInput{
+		AccountId: aws.String(ap.serviceClient.accountId),
+		Bucket:    ap.serviceClient.bucket.Name,
+	}); err != nil {
+		return nil, fmt.Errorf("generate bucket accessPoint: %w", err)
+	}
+
+	for ap.paginator.HasMorePages() {
+		page, err := ap.paginator.NextPage(context.Background())
+		if err != nil {
+			// The access points listed so far are kept along with the listing error
+			ap.Logger().Warn("failed to list access points", utils.ErrorArgs(err)...)
+			property, err := accessPointProperty(listAccessPointsLabel, accessPointDetail{
+				Errors: []string{lookupError("ListAccessPoints", err)},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("generate bucket accessPoint: %w", err)
+			}
+			properties = append(properties, property)
+			break
+		}
+
+		for _, point := range page.AccessPointList {
+			accessPointProps, err := ap.accessPointProperties(point)
+			if err != nil {
+				return nil, fmt.Errorf("generate bucket accessPoint: %w", err)
+			}
+			properties = append(properties, accessPointProps...)
+		}
+	}
+
+	return properties, nil
+}
+
+func (ap *accessPointMiner) accessPointProperties(
+	point types.AccessPoint,
+) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+	name := aws.ToString(point.Name)
+	accountId := aws.String(ap.serviceClient.accountId)
+	logger := ap.Logger().With("accessPoint", name)
+
+	// The listed access point is recorded when its detail cannot be read
+	content := accessPointDetail{
+		Name:             point.Name,
+		Alias:            point.Alias,
+		AccessPointArn:   point.AccessPointArn,
+		Bucket:           point.Bucket,
+		BucketAccountId:  point.BucketAccountId,
+		NetworkOrigin:    point.NetworkOrigin,
+		VpcConfiguration: point.VpcConfiguration,
+		Errors:           []string{},
+	}
+	lookupFailed := func(operation string, err error) {
+		logger.Warn("failed to look up access point", utils.ErrorArgs(err)...)
+		content.Errors = append(content.Errors, lookupError(operation, err))
+	}
+
+	detail, err := ap.serviceClient.control.GetAccessPoint(
+		context.Background(),
+		&s3control.GetAccessPointInput{AccountId: accountId, Name: aws.String(name)},
+	)
+	if err != nil {
+		lookupFailed("GetAccessPoint", err)
+	} else {
+		content = accessPointDetail{
+			Name:             detail.Name,
+			Alias:            detail.Alias,
+			AccessPointArn:   detail.AccessPointArn,
+			Bucket:           detail.Bucket,
+			BucketAccountId:  detail.BucketAccountId,
+			CreationDate:     detail.CreationDate,
+			Endpoints:        detail.Endpoints,
+			NetworkOrigin:    detail.NetworkOrigin,
+			VpcConfiguration: detail.VpcConfiguration,
+			Errors:           content.Errors,
+		}
+
+		if config := detail.PublicAccessBlockConfiguration; config != nil {
+			flags, err := publicAccessBlockFlags(
+				accessPointPublicAccessBlock,
+				name+valueSeparator,
+				config.BlockPublicAcls,
+				config.IgnorePublicAcls,
+				config.BlockPublicPolicy,
+				config.RestrictPublicBuckets,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("accessPointProperties %s: %w", name, err)
+			}
+			properties = append(properties, flags...)
+		}
+	}
+
+	policyOutput, err := ap.serviceClient.control.GetAccessPointPolicy(
+		context.Background(),
+		&s3control.GetAccessPointPolicyInput{AccountId: accountId, Name: aws.String(name)},
+	)
+	if err != nil && isNoSuchAccessPointPolicy(err) {
+		// Without policy there is no policy status either
+		property, err := accessPointProperty(name, content)
+		if err != nil {
+			return nil, fmt.Errorf("accessPointProperties %s: %w", name, err)
+		}
+		return append(properties, property), nil
+	}
+	if err != nil {
+		lookupFailed("GetAccessPointPolicy", err)
+	} else if policyOutput.Policy != nil {
+		normalizedPolicy, err := shared.JsonNormalize(aws.ToString(policyOutput.Policy))
+		if err != nil {
+			return nil, fmt.Errorf("accessPointProperties %s policy: %w", name, err)
+		}
+		properties = append(properties, shared.MinerProperty{
+			Type: accessPointPolicy,
+			Label: shared.MinerPropertyLabel{
+				Name:   name,
+				Unique: true,
+			},
+			Content: shared.MinerPropertyContent{
+				Format: shared.FormatJson,
+				Value:  string(normalizedPolicy),
+			},
+		})
+	}
+
+	status, err := ap.serviceClient.control.GetAccessPointPolicyStatus(
+		context.Background(),
+		&s3control.GetAccessPointPolicyStatusInput{AccountId: accountId, Name: aws.String(name)},
+	)
+	if err != nil && !isNoSuchAccessPointPolicy(err) {
+		lookupFailed("GetAccessPointPolicyStatus", err)
+	} else if err == nil && status.PolicyStatus != nil {
+		property := shared.MinerProperty{
+			Type: accessPointPolicyStatus,
+			Label: shared.MinerPropertyLabel{
+				Name:   name,
+				Unique: true,
+			},
+			Content: shared.MinerPropertyContent{
+				Format: shared.FormatJson,
+			},
+		}
+		if err := property.FormatContentValue(utils.CanonicalContent(status.PolicyStatus)); err != nil {
+			return nil, fmt.Errorf("accessPointProperties %s policyStatus: %w", name, err)
+		}
+		properties = append(properties, property)
+	}
+
+	property, err := accessPointProperty(name, content)
+	if err != nil {
+		return nil, fmt.Errorf("accessPointProperties %s: %w", name, err)
+	}
+
+	return append(properties, property), nil
+}
+
+// accessPointProperty builds the AccessPoint property labelled by access point name
+func accessPointProperty(label string, content accessPointDetail) (shared.MinerProperty, error) {
+	property := shared.MinerProperty{
+		Type: accessPoint,
+		Label: shared.MinerPropertyLabel{
+			Name:   label,
+			Unique: true,
+		},
+		Content: shared.MinerPropertyContent{
+			Format: shared.FormatJson,
+		},
+	}
+	if err := property.FormatContentValue(utils.CanonicalContent(content)); err != nil {
+		return shared.MinerProperty{}, fmt.Errorf("accessPointProperty: %w", err)
+	}
+
+	return property, nil
+}
+
+func isNoSuchAccessPointPolicy(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchAccessPointPolicy"
+}
+
+// multi-region access point detail
+// Multi-region access point report is cached from ListMultiRegionAccessPoints
+type multiRegionAccessPointDetailMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *s3ControlClient
+}
+
+func newMultiRegionAccessPointDetailMiner(
+	serviceClient utils.Client,
+	property string,
+) (*multiRegionAccessPointDetailMiner, error) {
+	client, err := assertS3ControlClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newMultiRegionAccessPointDetailMiner: %w", err)
+	}
+
+	return &multiRegionAccessPointDetailMiner{propertyType: property, serviceClient: client}, nil
+}
+
+func (mrd *multiRegionAccessPointDetailMiner) PropertyType() string { return mrd.propertyType }
+
+func (mrd *multiRegionAccessPointDetailMiner) FetchConf(input any) error { return nil }
+
+func (mrd *multiRegionAccessPointDetailMiner) Generate(
+	datum utils.CacheInfo,
+) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	property := shared.MinerProperty{
+		Type: multiRegionAccessPointDetail,
+		Label: shared.MinerPropertyLabel{
+			Name:   "MultiRegionAccessPoint",
+			Unique: true,
+		},
+		Content: shared.MinerPropertyContent{
+			Format: shared.FormatJson,
+			Value:  datum.Content,
+		},
+	}
+	properties = append(properties, property)
+
+	return properties, nil
+}
+
+// multi-region access point block public access settings
+type multiRegionAccessPointPublicAccessBlockMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *s3ControlClient
+	configuration *s3control.GetMultiRegionAccessPointOutput
+}
+
+func newMultiRegionAccessPointPublicAccessBlockMiner(
+	serviceClient utils.Client,
+	property string,
+) (*multiRegionAccessPointPublicAccessBlockMiner, error) {
+	client, err := assertS3ControlClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newMultiRegionAccessPointPublicAccessBlockMiner: %w", err)
+	}
+
+	return &multiRegionAccessPointPublicAccessBlockMiner{
+		propertyType:  property,
+		serviceClient: client,
+	}, nil
+}
+
+func (mrpab *multiRegionAccessPointPublicAccessBlockMiner) PropertyType() string {
+	return mrpab.propertyType
+}
+
+func (mrpab *multiRegionAccessPointPublicAccessBlockMiner) FetchConf(input any) error {
+	accessPointInput, ok := input.(*s3control.GetMultiRegionAccessPointInput)
+	if !ok {
+		return fmt.Errorf("fetchConf: GetMultiRegionAccessPointInput type assertion failed")
+	}
+
+	var err error
+	mrpab.configuration, err = mrpab.serviceClient.client.GetMultiRegionAccessPoint(
+		context.Background(),
+		accessPointInput,
+	)
+	if err != nil {
+		return fmt.Errorf("fetchConf multiRegionAccessPoint publicAccessBlock: %w", err)
+	}
+
+	return nil
+}
+
+func (mrpab *multiRegionAccessPointPublicAccessBlockMiner) Generate(
+	datum utils.CacheInfo,
+) ([]shared.MinerProperty, error) {
+	if err := mrpab.FetchConf(&s3control.GetMultiRegionAccessPointInput{
+		AccountId: aws.String(mrpab.serviceClient.accountId),
+		Name:      aws.String(datum.Name),
+	}); err != nil {
+		return nil, fmt.Errorf("generate multiRegionAccessPoint publicAccessBlock: %w", err)
+	}
+
+	if mrpab.configuration.AccessPoint == nil ||
+		mrpab.configuration.AccessPoint.PublicAccessBlock == nil {
+		return nil, &utils.MMError{
+			Category: multiRegionAccessPointPublicAccessBlock,
+			Code:     utils.NoConfig,
+		}
+	}
+
+	config := mrpab.configuration.AccessPoint.PublicAccessBlock
+	properties, err := publicAccessBlockFlags(
+		multiRegionAccessPointPublicAccessBlock,
+		"",
+		config.BlockPublicAcls,
+		config.IgnorePublicAcls,
+		config.BlockPublicPolicy,
+		config.RestrictPublicBuckets,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("generate multiRegionAccessPoint publicAccessBlock: %w", err)
+	}
+
+	return properties, nil
+}
+
+// multi-region access point established and proposed policies
+type multiRegionAccessPointPolicyMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *s3ControlClient
+	configuration *s3control.GetMultiRegionAccessPointPolicyOutput
+}
+
+func newMultiRegionAccessPointPolicyMiner(
+	serviceClient utils.Client,
+	property string,
+) (*multiRegionAccessPointPolicyMiner, error) {
+	client, err := assertS3ControlClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newMultiRegionAccessPointPolicyMiner: %w", err)
+	}
+
+	return &multiRegionAccessPointPolicyMiner{propertyType: property, serviceClient: client}, nil
+}
+
+func (mrp *multiRegionAccessPointPolicyMiner) PropertyType() string { return mrp.propertyType }
+
+func (mrp *multiRegionAccessPointPolicyMiner) FetchConf(input any) error {
+	policyInput, ok := input.(*s3control.GetMultiRegionAccessPointPolicyInput)
+	if !ok {
+		return fmt.Errorf("fetchConf: GetMultiRegionAccessPointPolicyInput type assertion failed")
+	}
+
+	var err error
+	mrp.configuration, err = mrp.serviceClient.client.GetMultiRegionAccessPointPolicy(
+		context.Background(),
+		policyInput,
+	)
+	if err != nil {
+		if isNoSuchAccessPointPolicy(err) {
+			return &utils.MMError{Category: multiRegionAccessPointPolicy, Code: utils.NoConfig}
+		}
+		return fmt.Errorf("fetchConf multiRegionAccessPoint policy: %w", err)
+	}
+
+	return nil
+}
+
+func (mrp *multiRegionAccessPointPolicyMiner) Generate(
+	datum utils.CacheInfo,
+) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	if err := mrp.FetchConf(&s3control.GetMultiRegionAccessPointPolicyInput{
+		AccountId: aws.String(mrp.serviceClient.accountId),
+		Name:      aws.String(datum.Name),
+	}); err != nil {
+		return nil, fmt.Errorf("generate multiRegionAccessPoint policy: %w", err)
+	}
+
+	document := mrp.configuration.Policy
+	if document == nil {
+		return properties, nil
+	}
+
+	policies := map[string]*string{}
+	if document.Established != nil {
+		policies["Established"] = document.Established.Policy
+	}
+	if document.Proposed != nil {
+		policies["Proposed"] = document.Proposed.Policy
+	}
+	for label, content := range policies {
+		if aws.ToString(content) == "" {
+			continue
+		}
+		normalizedPolicy, err := shared.JsonNormalize(aws.ToString(content))
+		if err != nil {
+			return nil, fmt.Errorf("generate multiRegionAccessPoint policy: %w", err)
+		}
+		properties = append(properties, shared.MinerProperty{
+			Type: multiRegionAccessPointPolicy,
+			Label: shared.MinerPropertyLabel{
+				Name:   label,
+				Unique: true,
+			},
+			Content: shared.MinerPropertyContent{
+				Format: shared.FormatJson,
+				Value:  string(normalizedPolicy),
+			},
+		})
+	}
+
+	return properties, nil
+}
+
+// multi-region access point established policy status
+type multiRegionAccessPointPolicyStatusMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *s3ControlClient
+	configuration *s3control.GetMultiRegionAccessPointPolicyStatusOutput
+}
+
+func newMultiRegionAccessPointPolicyStatusMiner(
+	serviceClient utils.Client,
+	property string,
+) (*multiRegionAccessPointPolicyStatusMiner, error) {
+	client, err := assertS3ControlClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newMultiRegionAccessPointPolicyStatusMiner: %w", err)
+	}
+
+	return &multiRegionAccessPointPolicyStatusMiner{
+		propertyType:  property,
+		serviceClient: client,
+	}, nil
+}
+
+func (mrps *multiRegionAccessPointPolicyStatusMiner) PropertyType() string {
+	return mrps.propertyType
+}
+
+func (mrps *multiRegionAccessPointPolicyStatusMiner) FetchConf(input any) error {
+	statusInput, ok := input.(*s3control.GetMultiRegionAccessPointPolicyStatusInput)
+	if !ok {
+		return fmt.Errorf(
+			"fetchConf: GetMultiRegionAccessPointPolicyStatusInput type assertion failed",
+		)
+	}
+
+	var err error
+	mrps.configuration, err = mrps.serviceClient.client.GetMultiRegionAccessPointPolicyStatus(
+		context.Background(),
+		statusInput,
+	)
+	if err != nil {
+		if isNoSuchAccessPointPolicy(err) {
+			return &utils.MMError{
+				Category: multiRegionAccessPointPolicyStatus,
+				Code:     utils.NoConfig,
+			}
+		}
+		return fmt.Errorf("fetchConf multiRegionAccessPoint policyStatus: %w", err)
+	}
+
+	return nil
+}
+
+func (mrps *multiRegionAccessPointPolicyStatusMiner) Generate(
+	datum utils.CacheInfo,
+) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	if err := mrps.FetchConf(&s3control.GetMultiRegionAccessPointPolicyStatusInput{
+		AccountId: aws.String(mrps.serviceClient.accountId),
+		Name:      aws.String(datum.Name),
+	}); err != nil {
+		return nil, fmt.Errorf("generate multiRegionAccessPoint policyStatus: %w", err)
+	}
+
+	if mrps.configuration.Established != nil {
+		property := shared.MinerProperty{
+			Type: multiRegionAccessPointPolicyStatus,
+			Label: shared.MinerPropertyLabel{
+				Name:   "Established",
+				Unique: true,
+			},
+			Content: shared.MinerPropertyContent{
+				Format: shared.FormatJson,
+			},
+		}
+		if err := property.FormatContentValue(utils.CanonicalContent(mrps.configuration.Established)); err != nil {
+			return nil, fmt.Errorf("generate multiRegionAccessPoint policyStatus: %w", err)
+		}
+		properties = append(properties, property)
+	}
+
+	return properties, nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3control"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+// testServerTransport sends every request to the test server, whatever the host prefix
+// added by the sdk
+type testServerTransport struct {
+	server *url.URL
+}
+
+func (t testServerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.server.Scheme
+	req.URL.Host = t.server.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func testAwsConfig(t *testing.T, handler http.HandlerFunc) aws.Config {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return aws.Config{
+		Region:           "us-east-1",
+		Credentials:      credentials.NewStaticCredentialsProvider("key", "secret", ""),
+		HTTPClient:       &http.Client{Transport: testServerTransport{server: serverURL}},
+		RetryMaxAttempts: 1,
+	}
+}
+
+func testErrorResponse(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `<ErrorResponse><Error><Code>%s</Code><Message>test</Message></Error></ErrorResponse>`, code)
+}
+
+func TestAccessPointMinerDegradesLookupErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantLabels []string
+		wantErrors map[string][]string
+	}{
+		{
+			name: "list access denied",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				testErrorResponse(w, http.StatusForbidden, "AccessDenied")
+			},
+			wantLabels: []string{listAccessPointsLabel},
+			wantErrors: map[string][]string{
+				listAccessPointsLabel: {"ListAccessPoints: AccessDenied"},
+			},
+		},
+		{
+			name: "detail denied and no policy",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/v20180820/accesspoint"):
+					fmt.Fprint(w, `<ListAccessPointsResult><AccessPointList><AccessPoint>`+
+						`<Name>reader</Name><NetworkOrigin>Internet</NetworkOrigin><Bucket>bucket</Bucket>`+
+						`</AccessPoint></AccessPointList></ListAccessPointsResult>`)
+				case strings.HasSuffix(r.URL.Path, "/v20180820/accesspoint/reader"):
+					testErrorResponse(w, http.StatusForbidden, "AccessDenied")
+				default:
+					testErrorResponse(w, http.StatusNotFound, "NoSuchAccessPointPolicy")
+				}
+			},
+			wantLabels: []string{"reader"},
+			wantErrors: map[string][]string{"reader": {"GetAccessPoint: AccessDenied"}},
+		},
+		{
+			name: "policy denied",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/v20180820/accesspoint"):
+					fmt.Fprint(w, `<ListAccessPointsResult><AccessPointList><AccessPoint>`+
+						`<Name>reader</Name><NetworkOrigin>Internet</NetworkOrigin><Bucket>bucket</Bucket>`+
+						`</AccessPoint></AccessPointList></ListAccessPointsResult>`)
+				case strings.HasSuffix(r.URL.Path, "/v20180820/accesspoint/reader"):
+					fmt.Fprint(w, `<GetAccessPointResult><Name>reader</Name><Bucket>bucket</Bucket>`+
+						`<NetworkOrigin>Internet</NetworkOrigin></GetAccessPointResult>`)
+				default:
+					testErrorResponse(w, http.StatusForbidden, "AccessDenied")
+				}
+			},
+			wantLabels: []string{"reader"},
+			wantErrors: map[string][]string{"reader": {
+				"GetAccessPointPolicy: AccessDenied",
+				"GetAccessPointPolicyStatus: AccessDenied",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			control := s3control.NewFromConfig(testAwsConfig(t, tt.handler))
+			client := newS3Client(
+				nil,
+				&types.Bucket{Name: aws.String("bucket")},
+				control,
+				testAccountId,
+				nil,
+				objectStatsConfig{},
+				nil,
+				nil,
+				utils.PartitionAws,
+			)
+			miner, err := newAccessPointMiner(client, accessPoint)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			properties, err := miner.Generate(utils.CacheInfo{})
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			labels := []string{}
+			for _, property := range properties {
+				if property.Type != accessPoint {
+					continue
+				}
+				labels = append(labels, property.Label.Name)
+				var detail accessPointDetail
+				if err := json.Unmarshal([]byte(property.Content.Value), &detail); err != nil {
+					t.Fatal(err)
+				}
+				if !slices.Equal(detail.Errors, tt.wantErrors[property.Label.Name]) {
+					t.Errorf("%s errors = %v, want %v", property.Label.Name, detail.Errors, tt.wantErrors[property.Label.Name])
+				}
+			}
+			if !slices.Equal(labels, tt.wantLabels) {
+				t.Errorf("Generate() labels = %v, want %v", labels, tt.wantLabels)
+			}
+		})
+	}
+}
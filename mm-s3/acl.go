@@ -3,13 +3,18 @@ package main
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/liuminhaw/mist-miner/shared"
 	"github.com/liuminhaw/mm-plugins/utils"
 )
 
 type aclMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *s3Client
 	configuration *s3.GetBucketAclOutput
@@ -63,18 +68,26 @@ func (a *aclMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerProperty, erro
 	}
 	properties = append(properties, property)
 
+	labels := map[string]bool{}
 	for _, grant := range a.configuration.Grants {
+		// Identical grants are equivalent, only keep the first one
+		label := aclGrantLabel(grant)
+		if labels[label] {
+			continue
+		}
+		labels[label] = true
+
 		property := shared.MinerProperty{
 			Type: acl,
 			Label: shared.MinerPropertyLabel{
-				Name:   "Grantee",
-				Unique: false,
+				Name:   label,
+				Unique: true,
 			},
 			Content: shared.MinerPropertyContent{
 				Format: shared.FormatJson,
 			},
 		}
-		if err := property.FormatContentValue(grant); err != nil {
+		if err := property.FormatContentValue(utils.CanonicalContent(grant)); err != nil {
 			return nil, fmt.Errorf("generate acl: %w", err)
 		}
 
@@ -83,3 +96,24 @@ func (a *aclMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerProperty, erro
 
 	return properties, nil
 }
+
+// aclGrantLabel labels a grant by grantee type, grantee id, uri or email and permission,
+// e.g. CanonicalUser|<id>|FULL_CONTROL
+func aclGrantLabel(grant types.Grant) string {
+	var granteeType types.Type
+	var grantee string
+	if grant.Grantee != nil {
+		granteeType = grant.Grantee.Type
+		for _, value := range []*string{grant.Grantee.ID, grant.Grantee.URI, grant.Grantee.EmailAddress} {
+			if aws.ToString(value) != "" {
+				grantee = aws.ToString(value)
+				break
+			}
+		}
+	}
+
+	return strings.Join(
+		[]string{string(granteeType), grantee, string(grant.Permission)},
+		valueSeparator,
+	)
+}
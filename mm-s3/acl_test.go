@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestAclGrantLabel(t *testing.T) {
+	tests := []struct {
+		name  string
+		grant types.Grant
+		want  string
+	}{
+		{
+			name: "canonical user",
+			grant: types.Grant{
+				Grantee: &types.Grantee{
+					Type:        types.TypeCanonicalUser,
+					ID:          aws.String(testOwnerId),
+					DisplayName: aws.String("owner"),
+				},
+				Permission: types.PermissionFullControl,
+			},
+			want: "CanonicalUser|owner-canonical-id|FULL_CONTROL",
+		},
+		{
+			name: "group",
+			grant: types.Grant{
+				Grantee: &types.Grantee{
+					Type: types.TypeGroup,
+					URI:  aws.String("http://acs.amazonaws.com/groups/s3/LogDelivery"),
+				},
+				Permission: types.PermissionWrite,
+			},
+			want: "Group|http://acs.amazonaws.com/groups/s3/LogDelivery|WRITE",
+		},
+		{
+			name: "email",
+			grant: types.Grant{
+				Grantee: &types.Grantee{
+					Type:         types.TypeAmazonCustomerByEmail,
+					EmailAddress: aws.String("user@example.com"),
+				},
+				Permission: types.PermissionRead,
+			},
+			want: "AmazonCustomerByEmail|user@example.com|READ",
+		},
+		{
+			name:  "no grantee",
+			grant: types.Grant{Permission: types.PermissionReadAcp},
+			want:  "||READ_ACP",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := aclGrantLabel(tt.grant); got != tt.want {
+				t.Errorf("aclGrantLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -11,6 +11,8 @@ import (
 )
 
 type analyticsMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *s3Client
 	configuration *s3.ListBucketAnalyticsConfigurationsOutput
@@ -72,7 +74,7 @@ func (a *analyticsMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerProperty
 					Format: shared.FormatJson,
 				},
 			}
-			if err := property.FormatContentValue(config); err != nil {
+			if err := property.FormatContentValue(utils.CanonicalContent(config)); err != nil {
 				return nil, fmt.Errorf("generate analytics: %w", err)
 			}
 
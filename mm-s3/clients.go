@@ -0,0 +1,76 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3control"
+	"github.com/hashicorp/go-hclog"
+	"github.com/liuminhaw/mist-miner/shared"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+// retryPolicies holds the retry policy of each service used by the plugin
+type retryPolicies struct {
+	s3      *utils.RetryPolicy
+	control *utils.RetryPolicy
+	kms     *utils.RetryPolicy
+}
+
+func newRetryPolicies(equipments []shared.MinerConfigEquipment) retryPolicies {
+	return retryPolicies{
+		s3:      utils.NewRetryPolicy(equipments, s3RetryService),
+		control: utils.NewRetryPolicy(equipments, s3controlRetryService),
+		kms:     utils.NewRetryPolicy(equipments, kmsRetryService),
+	}
+}
+
+// logSummary logs the retries and throttles of every service
+func (p retryPolicies) logSummary(logger hclog.Logger) {
+	for _, policy := range []*utils.RetryPolicy{p.s3, p.control, p.kms} {
+		policy.LogSummary(logger)
+	}
+}
+
+// regionalClient holds the service clients of a region
+type regionalClient struct {
+	s3      *s3.Client
+	control *s3control.Client
+	kms     *kms.Client
+}
+
+// regionalClients caches the service clients by region, built from the config loaded once
+// for the run, along with the kms keys resolved with them
+type regionalClients struct {
+	cfg     aws.Config
+	retry   retryPolicies
+	clients map[string]*regionalClient
+	kmsKeys *kmsKeyCache
+}
+
+func newRegionalClients(cfg aws.Config, retry retryPolicies) *regionalClients {
+	return &regionalClients{
+		cfg:     cfg,
+		retry:   retry,
+		clients: map[string]*regionalClient{},
+		kmsKeys: newKmsKeyCache(),
+	}
+}
+
+// get returns the clients of the region, building them on first use
+func (rc *regionalClients) get(region string) *regionalClient {
+	if client, ok := rc.clients[region]; ok {
+		return client
+	}
+
+	cfg := rc.cfg.Copy()
+	cfg.Region = region
+	client := &regionalClient{
+		s3:      s3.NewFromConfig(rc.retry.s3.Config(cfg)),
+		control: s3control.NewFromConfig(rc.retry.control.Config(cfg)),
+		kms:     kms.NewFromConfig(rc.retry.kms.Config(cfg)),
+	}
+	rc.clients[region] = client
+
+	return client
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	"github.com/liuminhaw/mist-miner/shared"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+// compatibleEndpoint is the s3-compatible storage (MinIO, Ceph RGW, LocalStack) setting
+// from the authenticator, the zero value targets aws
+type compatibleEndpoint struct {
+	url             string
+	region          string
+	pathStyle       bool
+	accessKeyId     string
+	secretAccessKey string
+}
+
+// newCompatibleEndpoint reads the s3-compatible endpoint from the authenticator, reporting
+// whether the endpoint is set
+func newCompatibleEndpoint(auth map[string]string) (compatibleEndpoint, bool) {
+	url, ok := auth["endpoint"]
+	if !ok || url == "" {
+		return compatibleEndpoint{}, false
+	}
+
+	endpoint := compatibleEndpoint{
+		url:             url,
+		region:          auth["region"],
+		pathStyle:       true,
+		accessKeyId:     auth["accessKeyId"],
+		secretAccessKey: auth["secretAccessKey"],
+	}
+	if endpoint.region == "" {
+		endpoint.region = defaultRegion
+	}
+	if pathStyle, err := strconv.ParseBool(auth["pathStyle"]); err == nil {
+		endpoint.pathStyle = pathStyle
+	}
+
+	return endpoint, true
+}
+
+// loadConfig loads the aws config of the endpoint, with the static credentials when given,
+// otherwise with the profile credentials
+func (e compatibleEndpoint) loadConfig(profile string) (aws.Config, error) {
+	optFns := []func(*config.LoadOptions) error{config.WithRegion(e.region)}
+	if e.accessKeyId != "" {
+		optFns = append(optFns, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(e.accessKeyId, e.secretAccessKey, ""),
+		))
+	} else if profile != "" {
+		optFns = append(optFns, config.WithSharedConfigProfile(profile))
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background(), optFns...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("loadConfig: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// s3Options points the s3 client to the endpoint, it leaves the aws options unchanged
+func (e compatibleEndpoint) s3Options(o *s3.Options) {
+	if e.url == "" {
+		return
+	}
+	o.BaseEndpoint = aws.String(e.url)
+	o.UsePathStyle = e.pathStyle
+}
+
+// compatibleMiner degrades the apis not implemented by the s3-compatible backend to
+// missing configurations
+type compatibleMiner struct {
+	utils.PropsCrawler
+}
+
+func (c compatibleMiner) Generate(datum utils.CacheInfo) ([]shared.MinerProperty, error) {
+	properties, err := c.PropsCrawler.Generate(datum)
+	if err != nil && isNotImplemented(err) {
+		return nil, &utils.MMError{Category: c.PropertyType(), Code: utils.NoConfig}
+	}
+
+	return properties, err
+}
+
+// compatibleConstructors wraps the property miners constructors with compatibleMiner
+func compatibleConstructors(
+	constructors []utils.PropsCrawlerConstructor,
+) []utils.PropsCrawlerConstructor {
+	wrapped := []utils.PropsCrawlerConstructor{}
+	for _, constructor := range constructors {
+		wrapped = append(wrapped, func(client utils.Client) (utils.PropsCrawler, error) {
+			miner, err := constructor(client)
+			if err != nil {
+				return nil, err
+			}
+			return compatibleMiner{PropsCrawler: miner}, nil
+		})
+	}
+
+	return wrapped
+}
+
+func isNotImplemented(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NotImplemented" {
+		return true
+	}
+
+	var responseErr *awshttp.ResponseError
+	return errors.As(err, &responseErr) &&
+		responseErr.HTTPStatusCode() == http.StatusNotImplemented
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+func TestCompatibleMinerNotImplemented(t *testing.T) {
+	s3Error := func(status int, code string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			if code != "" {
+				fmt.Fprintf(w, `<Error><Code>%s</Code><Message>test</Message></Error>`, code)
+			}
+		}
+	}
+
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantNoConf bool
+		wantErr    bool
+		wantProps  int
+	}{
+		{
+			name:       "not implemented code",
+			handler:    s3Error(http.StatusNotImplemented, "NotImplemented"),
+			wantNoConf: true,
+		},
+		{
+			name:       "not implemented status without body",
+			handler:    s3Error(http.StatusNotImplemented, ""),
+			wantNoConf: true,
+		},
+		{
+			name:       "missing configuration",
+			handler:    s3Error(http.StatusNotFound, "NoSuchCORSConfiguration"),
+			wantNoConf: true,
+		},
+		{
+			name:    "access denied",
+			handler: s3Error(http.StatusForbidden, "AccessDenied"),
+			wantErr: true,
+		},
+		{
+			name: "implemented",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `<CORSConfiguration><CORSRule><AllowedOrigin>*</AllowedOrigin>`+
+					`<AllowedMethod>GET</AllowedMethod></CORSRule></CORSConfiguration>`)
+			},
+			wantProps: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newS3Client(
+				s3.NewFromConfig(testAwsConfig(t, tt.handler)),
+				&types.Bucket{Name: aws.String("bucket")},
+				nil,
+				"",
+				nil,
+				objectStatsConfig{},
+				nil,
+				nil,
+				utils.PartitionAws,
+			)
+			constructors := compatibleConstructors([]utils.PropsCrawlerConstructor{
+				func(client utils.Client) (utils.PropsCrawler, error) {
+					return newCorsMiner(client, cors)
+				},
+			})
+			miner, err := constructors[0](client)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			properties, err := miner.Generate(utils.CacheInfo{})
+			var configErr *utils.MMError
+			isNoConf := errors.As(err, &configErr) && configErr.Code == utils.NoConfig
+			switch {
+			case tt.wantNoConf:
+				if !isNoConf || configErr.Category != cors {
+					t.Errorf("Generate() error = %v, want %s NoConfig", err, cors)
+				}
+			case tt.wantErr:
+				if err == nil || isNoConf {
+					t.Errorf("Generate() error = %v, want the api error", err)
+				}
+			default:
+				if err != nil || len(properties) != tt.wantProps {
+					t.Errorf("Generate() = %d properties, %v, want %d", len(properties), err, tt.wantProps)
+				}
+			}
+		})
+	}
+}
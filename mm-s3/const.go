@@ -2,6 +2,7 @@ package main
 
 const (
 	accelerateConfig   = "AccelerateConfig"
+	accessPoint        = "AccessPoint"
 	analyticsConfig    = "AnalyticsConfig"
 	acl                = "Acl"
 	cors               = "CORS"
@@ -12,15 +13,78 @@ const (
 	location           = "Location"
 	logging            = "Logging"
 	metrics            = "Metrics"
+	objectLock         = "ObjectLock"
 	notification       = "Notification"
 	ownershipControl   = "OwnershipControl"
 	policy             = "Policy"
 	policyStatus       = "PolicyStatus"
+	publicAccessBlock  = "PublicAccessBlock"
 	replication        = "Replication"
 	requestPayment     = "RequestPayment"
 	tagging            = "Tag"
 	versioning         = "Versioning"
 	website            = "Website"
 
+	// public exposure
+	publicExposureProperty = "PublicExposure"
+	exposurePrivate        = "private"
+	exposurePublicRead     = "public-read"
+	exposurePublicWrite    = "public-write"
+	exposureCrossAccount   = "cross-account"
+
+	// bucket resources, counted when vanished during mining
+	bucketResourceType = "Bucket"
+
+	// account level
+	accountResource          = "Account"
+	accountPublicAccessBlock = "AccountPublicAccessBlock"
+
+	// access points
+	accessPointPublicAccessBlock            = "AccessPointPublicAccessBlock"
+	listAccessPointsLabel                   = "ListAccessPoints"
+	accessPointPolicy                       = "AccessPointPolicy"
+	accessPointPolicyStatus                 = "AccessPointPolicyStatus"
+	multiRegionAccessPointResource          = "MultiRegionAccessPoint"
+	multiRegionAccessPointDetail            = "MultiRegionAccessPointDetail"
+	multiRegionAccessPointPublicAccessBlock = "MultiRegionAccessPointPublicAccessBlock"
+	multiRegionAccessPointPolicy            = "MultiRegionAccessPointPolicy"
+	multiRegionAccessPointPolicyStatus      = "MultiRegionAccessPointPolicyStatus"
+
+	// Multi-Region Access Point control plane requests are routed to us-west-2
+	multiRegionAccessPointRegion = "us-west-2"
+
+	defaultRegion = "us-east-1"
+
 	valueSeparator = "|"
 )
+
+// object statistics
+const (
+	objectStatsProperty   = "ObjectStats"
+	bucketEquipmentType   = "bucket"
+	defaultObjectStatsCap = 10000
+)
+
+// lifecycle analysis
+const (
+	lifecycleAnalysisType          = "LifecycleAnalysis"
+	lifecycleNoncurrentNeverExpire = "NoncurrentVersionsNeverExpire"
+	lifecycleMultipartNotAborted   = "IncompleteMultipartUploadsNotAborted"
+	lifecycleRuleConflict          = "RuleConflict"
+)
+
+// replication
+const (
+	replicationRole      = "ReplicationRole"
+	replicationCheckType = "ReplicationCheck"
+)
+
+// aws managed key used by kms encryption without a key id
+const awsManagedS3Key = "alias/aws/s3"
+
+// Names of the services in the retry equipment
+const (
+	s3RetryService        = "s3"
+	s3controlRetryService = "s3control"
+	kmsRetryService       = "kms"
+)
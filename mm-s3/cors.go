@@ -2,11 +2,12 @@ package main
 
 import (
 	"context"
-	"crypto/md5"
 	"errors"
 	"fmt"
 	"sort"
+	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/smithy-go"
@@ -15,6 +16,8 @@ import (
 )
 
 type corsMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *s3Client
 	configuration *s3.GetBucketCorsOutput
@@ -64,32 +67,48 @@ func (c *corsMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerProperty, err
 	if err := c.FetchConf(&s3.GetBucketCorsInput{Bucket: c.serviceClient.bucket.Name}); err != nil {
 		return nil, fmt.Errorf("generate bucket cors: %w", err)
 	}
+	labels := map[string]int{}
 	for _, rule := range c.configuration.CORSRules {
 		sortCorsRule(&rule)
 
+		// Rules sharing the same label are numbered in their configuration order
+		label := corsRuleLabel(rule)
+		labels[label]++
+		if labels[label] > 1 {
+			label = fmt.Sprintf("%s%s%d", label, valueSeparator, labels[label])
+		}
+
 		property := shared.MinerProperty{
 			Type: cors,
 			Label: shared.MinerPropertyLabel{
-				Unique: false,
+				Name:   label,
+				Unique: true,
 			},
 			Content: shared.MinerPropertyContent{
 				Format: shared.FormatJson,
 			},
 		}
-		if err := property.FormatContentValue(rule); err != nil {
+		if err := property.FormatContentValue(utils.CanonicalContent(rule)); err != nil {
 			return nil, fmt.Errorf("generate bucket cors: %w", err)
 		}
 
-		h := md5.New()
-		h.Write([]byte(property.Content.Value))
-		property.Label.Name = fmt.Sprintf("%x", h.Sum(nil))
-
 		properties = append(properties, property)
 	}
 
 	return properties, nil
 }
 
+// corsRuleLabel labels a rule by its id when set, otherwise by its allowed origins and methods,
+// e.g. https://example.com,https://example.org|GET,PUT
+func corsRuleLabel(rule types.CORSRule) string {
+	if id := aws.ToString(rule.ID); id != "" {
+		return id
+	}
+
+	return strings.Join(rule.AllowedOrigins, ",") + valueSeparator +
+		strings.Join(rule.AllowedMethods, ",")
+}
+
 func sortCorsRule(rule *types.CORSRule) {
 	sort.Strings(rule.AllowedMethods)
 	sort.Strings(rule.AllowedOrigins)
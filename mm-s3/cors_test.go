@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+func TestCorsRuleLabel(t *testing.T) {
+	tests := []struct {
+		name string
+		rule types.CORSRule
+		want string
+	}{
+		{
+			name: "id",
+			rule: types.CORSRule{
+				ID:             aws.String("uploads"),
+				AllowedOrigins: []string{"https://example.com"},
+				AllowedMethods: []string{"PUT"},
+			},
+			want: "uploads",
+		},
+		{
+			name: "origins and methods",
+			rule: types.CORSRule{
+				AllowedOrigins: []string{"https://example.com", "https://example.org"},
+				AllowedMethods: []string{"GET", "PUT"},
+			},
+			want: "https://example.com,https://example.org|GET,PUT",
+		},
+		{
+			name: "empty id",
+			rule: types.CORSRule{
+				ID:             aws.String(""),
+				AllowedOrigins: []string{"*"},
+				AllowedMethods: []string{"GET"},
+			},
+			want: "*|GET",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := corsRuleLabel(tt.rule); got != tt.want {
+				t.Errorf("corsRuleLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCorsMinerLabels(t *testing.T) {
+	// Origins and methods are sorted before labelling, and rules sharing a label are
+	// numbered in their configuration order
+	rules := `<CORSConfiguration>` +
+		`<CORSRule><AllowedOrigin>https://example.org</AllowedOrigin>` +
+		`<AllowedOrigin>https://example.com</AllowedOrigin>` +
+		`<AllowedMethod>PUT</AllowedMethod><AllowedMethod>GET</AllowedMethod></CORSRule>` +
+		`<CORSRule><AllowedOrigin>https://example.com</AllowedOrigin>` +
+		`<AllowedOrigin>https://example.org</AllowedOrigin>` +
+		`<AllowedMethod>GET</AllowedMethod><AllowedMethod>PUT</AllowedMethod>` +
+		`<MaxAgeSeconds>300</MaxAgeSeconds></CORSRule>` +
+		`<CORSRule><ID>uploads</ID><AllowedOrigin>*</AllowedOrigin>` +
+		`<AllowedMethod>POST</AllowedMethod></CORSRule>` +
+		`</CORSConfiguration>`
+	cfg := testAwsConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, rules)
+	})
+	client := newS3Client(
+		s3.NewFromConfig(cfg),
+		&types.Bucket{Name: aws.String("bucket")},
+		nil,
+		testAccountId,
+		nil,
+		objectStatsConfig{},
+		nil,
+		nil,
+		utils.PartitionAws,
+	)
+	miner, err := newCorsMiner(client, cors)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	properties, err := miner.Generate(utils.CacheInfo{})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	labels := []string{}
+	for _, property := range properties {
+		if !property.Label.Unique {
+			t.Errorf("%s label is not unique", property.Label.Name)
+		}
+		labels = append(labels, property.Label.Name)
+	}
+	want := []string{
+		"https://example.com,https://example.org|GET,PUT",
+		"https://example.com,https://example.org|GET,PUT|2",
+		"uploads",
+	}
+	if !slices.Equal(labels, want) {
+		t.Errorf("Generate() labels = %v, want %v", labels, want)
+	}
+}
@@ -2,14 +2,21 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	"github.com/liuminhaw/mist-miner/shared"
 	"github.com/liuminhaw/mm-plugins/utils"
 )
 
 type encryptionMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *s3Client
 	configuration *s3.GetBucketEncryptionOutput
@@ -38,6 +45,15 @@ func (e *encryptionMiner) FetchConf(input any) error {
 		bucketEncryptionInput,
 	)
 	if err != nil {
+		var apiErr smithy.APIError
+		if ok := errors.As(err, &apiErr); ok {
+			switch apiErr.ErrorCode() {
+			case "ServerSideEncryptionConfigurationNotFoundError":
+				return &utils.MMError{Category: encryption, Code: utils.NoConfig}
+			default:
+				return fmt.Errorf("fetchConf bucket encryption: %w", err)
+			}
+		}
 		return fmt.Errorf("fetchConf bucket encryption: %w", err)
 	}
 
@@ -51,21 +67,100 @@ func (e *encryptionMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerPropert
 		return nil, fmt.Errorf("generate bucket encryption: %w", err)
 	}
 
+	labels := map[string]int{}
 	for _, rule := range e.configuration.ServerSideEncryptionConfiguration.Rules {
+		// Rules sharing the same label are numbered in their configuration order
+		label := encryptionRuleLabel(rule)
+		labels[label]++
+		if labels[label] > 1 {
+			label = fmt.Sprintf("%s%s%d", label, valueSeparator, labels[label])
+		}
+
+		ruleProperties, err := e.ruleProperties(label, rule)
+		if err != nil {
+			return nil, fmt.Errorf("generate bucket encryption: %w", err)
+		}
+		properties = append(properties, ruleProperties...)
+	}
+
+	return properties, nil
+}
+
+// encryptionRuleLabel labels a rule by its algorithm and kms key id when set,
+// e.g. aws:kms|alias/data, or AES256
+func encryptionRuleLabel(rule types.ServerSideEncryptionRule) string {
+	byDefault := rule.ApplyServerSideEncryptionByDefault
+	if byDefault == nil {
+		return "None"
+	}
+	if keyId := aws.ToString(byDefault.KMSMasterKeyID); keyId != "" {
+		return string(byDefault.SSEAlgorithm) + valueSeparator + keyId
+	}
+
+	return string(byDefault.SSEAlgorithm)
+}
+
+// encryptionField is a field of an encryption rule, mined as its own property
+type encryptionField struct {
+	name    string
+	format  string
+	content any
+}
+
+// ruleProperties splits the rule into a property by field, labelled <rule label>|<field>.
+// For kms algorithms the key, or the aws managed aws/s3 key without a key id, is resolved
+// into the KmsKey property.
+func (e *encryptionMiner) ruleProperties(
+	label string,
+	rule types.ServerSideEncryptionRule,
+) ([]shared.MinerProperty, error) {
+	fields := []encryptionField{
+		{"BucketKeyEnabled", shared.FormatText, strconv.FormatBool(aws.ToBool(rule.BucketKeyEnabled))},
+	}
+
+	if byDefault := rule.ApplyServerSideEncryptionByDefault; byDefault != nil {
+		fields = append(
+			fields,
+			encryptionField{"SSEAlgorithm", shared.FormatText, string(byDefault.SSEAlgorithm)},
+		)
+
+		keyId := aws.ToString(byDefault.KMSMasterKeyID)
+		if keyId != "" {
+			fields = append(fields, encryptionField{"KMSMasterKeyID", shared.FormatText, keyId})
+		}
+
+		if byDefault.SSEAlgorithm == types.ServerSideEncryptionAwsKms ||
+			byDefault.SSEAlgorithm == types.ServerSideEncryptionAwsKmsDsse {
+			if keyId == "" {
+				keyId = awsManagedS3Key
+			}
+			var keys *kmsKeyCache
+			if e.serviceClient.regional != nil {
+				keys = e.serviceClient.regional.kmsKeys
+			}
+			key := resolveKmsKey(e.serviceClient.kms, keys, keyId)
+			fields = append(
+				fields,
+				encryptionField{"KmsKey", shared.FormatJson, utils.CanonicalContent(key)},
+			)
+		}
+	}
+
+	properties := []shared.MinerProperty{}
+	for _, field := range fields {
 		property := shared.MinerProperty{
 			Type: encryption,
 			Label: shared.MinerPropertyLabel{
-				Name:   "Rule",
-				Unique: false,
+				Name:   label + valueSeparator + field.name,
+				Unique: true,
 			},
 			Content: shared.MinerPropertyContent{
-				Format: shared.FormatJson,
+				Format: field.format,
 			},
 		}
-		if err := property.FormatContentValue(rule); err != nil {
-			return nil, fmt.Errorf("generate bucket encryption: %w", err)
+		if err := property.FormatContentValue(field.content); err != nil {
+			return nil, fmt.Errorf("encryption rule %s: %w", label, err)
 		}
-
 		properties = append(properties, property)
 	}
 
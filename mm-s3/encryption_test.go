@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+func TestEncryptionRuleLabel(t *testing.T) {
+	tests := []struct {
+		name string
+		rule types.ServerSideEncryptionRule
+		want string
+	}{
+		{
+			name: "s3 managed key",
+			rule: types.ServerSideEncryptionRule{
+				ApplyServerSideEncryptionByDefault: &types.ServerSideEncryptionByDefault{
+					SSEAlgorithm: types.ServerSideEncryptionAes256,
+				},
+			},
+			want: "AES256",
+		},
+		{
+			name: "kms key",
+			rule: types.ServerSideEncryptionRule{
+				ApplyServerSideEncryptionByDefault: &types.ServerSideEncryptionByDefault{
+					SSEAlgorithm:   types.ServerSideEncryptionAwsKms,
+					KMSMasterKeyID: aws.String("alias/data"),
+				},
+			},
+			want: "aws:kms|alias/data",
+		},
+		{
+			name: "kms without key id",
+			rule: types.ServerSideEncryptionRule{
+				ApplyServerSideEncryptionByDefault: &types.ServerSideEncryptionByDefault{
+					SSEAlgorithm:   types.ServerSideEncryptionAwsKms,
+					KMSMasterKeyID: aws.String(""),
+				},
+			},
+			want: "aws:kms",
+		},
+		{name: "no default encryption", rule: types.ServerSideEncryptionRule{}, want: "None"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := encryptionRuleLabel(tt.rule); got != tt.want {
+				t.Errorf("encryptionRuleLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncryptionMinerLabels(t *testing.T) {
+	// Each rule field is its own property, rules sharing a label are numbered in their
+	// configuration order
+	rules := `<ServerSideEncryptionConfiguration>` +
+		`<Rule><ApplyServerSideEncryptionByDefault><SSEAlgorithm>AES256</SSEAlgorithm>` +
+		`</ApplyServerSideEncryptionByDefault><BucketKeyEnabled>false</BucketKeyEnabled></Rule>` +
+		`<Rule><ApplyServerSideEncryptionByDefault><SSEAlgorithm>AES256</SSEAlgorithm>` +
+		`</ApplyServerSideEncryptionByDefault></Rule>` +
+		`</ServerSideEncryptionConfiguration>`
+	cfg := testAwsConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, rules)
+	})
+	client := newS3Client(
+		s3.NewFromConfig(cfg),
+		&types.Bucket{Name: aws.String("bucket")},
+		nil,
+		testAccountId,
+		nil,
+		objectStatsConfig{},
+		nil,
+		nil,
+		utils.PartitionAws,
+	)
+	miner, err := newEncryptionMiner(client, encryption)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	properties, err := miner.Generate(utils.CacheInfo{})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	labels := []string{}
+	for _, property := range properties {
+		labels = append(labels, property.Label.Name+"="+property.Content.Value)
+	}
+	want := []string{
+		"AES256|BucketKeyEnabled=false",
+		"AES256|SSEAlgorithm=AES256",
+		"AES256|2|BucketKeyEnabled=false",
+		"AES256|2|SSEAlgorithm=AES256",
+	}
+	if !slices.Equal(labels, want) {
+		t.Errorf("Generate() properties = %v, want %v", labels, want)
+	}
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/liuminhaw/mist-miner/shared"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+var accountIdPattern = regexp.MustCompile(`^\d{12}$`)
+
+// blockPublicAccess holds the block public access settings, keyed by setting name
+type blockPublicAccess map[string]bool
+
+// accountExposure is the account level information used to evaluate bucket exposure
+type accountExposure struct {
+	accountId         string
+	blockPublicAccess blockPublicAccess
+}
+
+type exposureReason struct {
+	Exposure string
+	Source   string
+	Detail   string
+	// Grant or Statement responsible for the exposure
+	Grant     *types.Grant           `json:",omitempty"`
+	Statement *utils.PolicyStatement `json:",omitempty"`
+}
+
+type bucketExposure struct {
+	Verdict string
+	Reasons []exposureReason
+	// Exposures neutralized by block public access or object ownership settings
+	Mitigated         []exposureReason
+	BlockPublicAccess blockPublicAccess
+	AclsDisabled      bool
+	WebsiteEnabled    bool
+}
+
+// newBlockPublicAccess reads the block public access flag properties of a resource
+func newBlockPublicAccess(resource shared.MinerResource, propertyType string) blockPublicAccess {
+	settings := blockPublicAccess{}
+	for _, property := range resource.Properties {
+		if property.Type == propertyType {
+			settings[property.Label.Name] = property.Content.Value == "true"
+		}
+	}
+
+	return settings
+}
+
+// publicExposure evaluates the effective exposure of a mined bucket by joining the account
+// and bucket block public access settings, object ownership, acl grants, bucket policy,
+// policy status and website configuration.
+func publicExposure(
+	resource shared.MinerResource,
+	account accountExposure,
+) (shared.MinerProperty, error) {
+	exposure := bucketExposure{
+		Reasons:           []exposureReason{},
+		Mitigated:         []exposureReason{},
+		BlockPublicAccess: blockPublicAccess{},
+	}
+
+	bucketSettings := newBlockPublicAccess(resource, publicAccessBlock)
+	for _, setting := range []string{
+		"BlockPublicAcls", "IgnorePublicAcls", "BlockPublicPolicy", "RestrictPublicBuckets",
+	} {
+		exposure.BlockPublicAccess[setting] = account.blockPublicAccess[setting] ||
+			bucketSettings[setting]
+	}
+
+	var aclOwner string
+	var policyIsPublic *bool
+	grants := []types.Grant{}
+	policyDocument := ""
+	for _, property := range resource.Properties {
+		switch property.Type {
+		case acl:
+			if property.Label.Name == "Owner" {
+				aclOwner = property.Content.Value
+				continue
+			}
+			var grant types.Grant
+			if err := json.Unmarshal([]byte(property.Content.Value), &grant); err != nil {
+				return shared.MinerProperty{}, fmt.Errorf("publicExposure: %w", err)
+			}
+			grants = append(grants, grant)
+		case ownershipControl:
+			var controls types.OwnershipControls
+			if err := json.Unmarshal([]byte(property.Content.Value), &controls); err != nil {
+				return shared.MinerProperty{}, fmt.Errorf("publicExposure: %w", err)
+			}
+			for _, rule := range controls.Rules {
+				if rule.ObjectOwnership == types.ObjectOwnershipBucketOwnerEnforced {
+					exposure.AclsDisabled = true
+				}
+			}
+		case policy:
+			policyDocument = property.Content.Value
+		case policyStatus:
+			var status types.PolicyStatus
+			if err := json.Unmarshal([]byte(property.Content.Value), &status); err != nil {
+				return shared.MinerProperty{}, fmt.Errorf("publicExposure: %w", err)
+			}
+			policyIsPublic = status.IsPublic
+		case website:
+			exposure.WebsiteEnabled = true
+		}
+	}
+
+	for i := range grants {
+		reasons := grantExposure(grants[i], aclOwner)
+		for _, reason := range reasons {
+			switch {
+			case exposure.AclsDisabled:
+				reason.Detail += ", acls disabled by BucketOwnerEnforced object ownership"
+				exposure.Mitigated = append(exposure.Mitigated, reason)
+			case reason.Exposure != exposureCrossAccount && exposure.BlockPublicAccess["IgnorePublicAcls"]:
+				reason.Detail += ", ignored by IgnorePublicAcls"
+				exposure.Mitigated = append(exposure.Mitigated, reason)
+			default:
+				exposure.Reasons = append(exposure.Reasons, reason)
+			}
+		}
+	}
+
+	if policyDocument != "" {
+		document, err := utils.ParsePolicyDocument(policyDocument)
+		if err != nil {
+			return shared.MinerProperty{}, fmt.Errorf("publicExposure: %w", err)
+		}
+		for i := range document.Statement {
+			reasons := statementExposure(document.Statement[i], account.accountId, policyIsPublic)
+			for _, reason := range reasons {
+				if reason.Exposure != exposureCrossAccount &&
+					exposure.BlockPublicAccess["RestrictPublicBuckets"] {
+					reason.Detail += ", restricted by RestrictPublicBuckets"
+					exposure.Mitigated = append(exposure.Mitigated, reason)
+					continue
+				}
+				exposure.Reasons = append(exposure.Reasons, reason)
+			}
+		}
+	}
+
+	exposure.Verdict = exposurePrivate
+	for _, verdict := range []string{exposureCrossAccount, exposurePublicRead, exposurePublicWrite} {
+		for _, reason := range exposure.Reasons {
+			if reason.Exposure == verdict {
+				exposure.Verdict = verdict
+			}
+		}
+	}
+	if exposure.WebsiteEnabled && exposure.Verdict != exposurePrivate &&
+		exposure.Verdict != exposureCrossAccount {
+		exposure.Reasons = append(exposure.Reasons, exposureReason{
+			Exposure: exposurePublicRead,
+			Source:   website,
+			Detail:   "static website hosting serves the publicly readable objects",
+		})
+	}
+
+	property := shared.MinerProperty{
+		Type: publicExposureProperty,
+		Label: shared.MinerPropertyLabel{
+			Name:   "PublicExposure",
+			Unique: true,
+		},
+		Content: shared.MinerPropertyContent{
+			Format: shared.FormatJson,
+		},
+	}
+	if err := property.FormatContentValue(utils.CanonicalContent(exposure)); err != nil {
+		return shared.MinerProperty{}, fmt.Errorf("publicExposure: %w", err)
+	}
+
+	return property, nil
+}
+
+// grantExposure classifies an acl grant given to everyone, to any authenticated aws user
+// or to another canonical user than the bucket owner.
+func grantExposure(grant types.Grant, owner string) []exposureReason {
+	if grant.Grantee == nil {
+		return nil
+	}
+
+	var grantee string
+	public := false
+	switch grant.Grantee.Type {
+	case types.TypeGroup:
+		uri := strings.ToLower(aws.ToString(grant.Grantee.URI))
+		switch {
+		case strings.HasSuffix(uri, "/global/allusers"):
+			grantee = "AllUsers"
+			public = true
+		case strings.HasSuffix(uri, "/global/authenticatedusers"):
+			grantee = "AuthenticatedUsers"
+			public = true
+		default:
+			return nil
+		}
+	case types.TypeCanonicalUser:
+		if aws.ToString(grant.Grantee.ID) == owner {
+			return nil
+		}
+		grantee = "canonical user " + aws.ToString(grant.Grantee.ID)
+	case types.TypeAmazonCustomerByEmail:
+		grantee = aws.ToString(grant.Grantee.EmailAddress)
+	default:
+		return nil
+	}
+
+	reasons := []exposureReason{}
+	read, write := permissionAccess(grant.Permission)
+	for _, access := range []struct {
+		enabled  bool
+		exposure string
+	}{
+		{enabled: read, exposure: exposurePublicRead},
+		{enabled: write, exposure: exposurePublicWrite},
+	} {
+		if !access.enabled {
+			continue
+		}
+		exposure := access.exposure
+		if !public {
+			exposure = exposureCrossAccount
+		}
+		reasons = append(reasons, exposureReason{
+			Exposure: exposure,
+			Source:   acl,
+			Detail:   fmt.Sprintf("acl grants %s to %s", grant.Permission, grantee),
+			Grant:    &grant,
+		})
+		if !public {
+			break
+		}
+	}
+
+	return reasons
+}
+
+func permissionAccess(permission types.Permission) (read, write bool) {
+	switch permission {
+	case types.PermissionFullControl:
+		return true, true
+	case types.PermissionRead, types.PermissionReadAcp:
+		return true, false
+	case types.PermissionWrite, types.PermissionWriteAcp:
+		return false, true
+	}
+	return false, false
+}
+
+// statementExposure classifies an allow statement of the bucket policy. Statements with a
+// wildcard principal are public unless the policy status reports the policy as not public
+// (conditions restricting the access). Statements granting other accounts are cross-account.
+func statementExposure(
+	statement utils.PolicyStatement,
+	accountId string,
+	policyIsPublic *bool,
+) []exposureReason {
+	if statement.Effect != "Allow" {
+		return nil
+	}
+
+	reasons := []exposureReason{}
+	read, write := statementAccess(statement)
+
+	wildcard := len(statement.NotPrincipal) > 0
+	externalAccounts := []string{}
+	for _, principalType := range statement.Principal.Types() {
+		if principalType != "AWS" {
+			continue
+		}
+		for _, principal := range statement.Principal[principalType] {
+			if principal == "*" {
+				wildcard = true
+				continue
+			}
+			if principalAccount := arnAccountId(principal); principalAccount != "" &&
+				accountId != "" && principalAccount != accountId {
+				externalAccounts = append(externalAccounts, principal)
+			}
+		}
+	}
+
+	if wildcard && (policyIsPublic == nil || *policyIsPublic) {
+		if read {
+			reasons = append(reasons, exposureReason{
+				Exposure:  exposurePublicRead,
+				Source:    policy,
+				Detail:    fmt.Sprintf("statement %s allows read access to any principal", statement.Sid),
+				Statement: &statement,
+			})
+		}
+		if write {
+			reasons = append(reasons, exposureReason{
+				Exposure:  exposurePublicWrite,
+				Source:    policy,
+				Detail:    fmt.Sprintf("statement %s allows write access to any principal", statement.Sid),
+				Statement: &statement,
+			})
+		}
+	}
+	if len(externalAccounts) > 0 && (read || write) {
+		reasons = append(reasons, exposureReason{
+			Exposure: exposureCrossAccount,
+			Source:   policy,
+			Detail: fmt.Sprintf(
+				"statement %s allows access to %s",
+				statement.Sid,
+				strings.Join(externalAccounts, ", "),
+			),
+			Statement: &statement,
+		})
+	}
+
+	return reasons
+}
+
+// statementAccess reports whether the statement actions grant read or write access
+func statementAccess(statement utils.PolicyStatement) (read, write bool) {
+	if len(statement.NotAction) > 0 {
+		return true, true
+	}
+
+	for _, action := range statement.Action {
+		action = strings.ToLower(action)
+		switch {
+		case action == "*" || action == "s3:*":
+			return true, true
+		case strings.HasPrefix(action, "s3:get"), strings.HasPrefix(action, "s3:list"):
+			read = true
+		case strings.HasPrefix(action, "s3:put"),
+			strings.HasPrefix(action, "s3:delete"),
+			strings.HasPrefix(action, "s3:abort"),
+			strings.HasPrefix(action, "s3:restore"),
+			strings.HasPrefix(action, "s3:replicate"):
+			write = true
+		}
+	}
+
+	return read, write
+}
+
+// arnAccountId returns the account id of an aws principal given as account id or arn
+func arnAccountId(principal string) string {
+	if accountIdPattern.MatchString(principal) {
+		return principal
+	}
+
+	parts := strings.Split(principal, ":")
+	if len(parts) >= 5 && parts[0] == "arn" && accountIdPattern.MatchString(parts[4]) {
+		return parts[4]
+	}
+
+	return ""
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/liuminhaw/mist-miner/shared"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+const (
+	testAccountId = "111122223333"
+	testOwnerId   = "owner-canonical-id"
+)
+
+func testProperty(propertyType, label, value string) shared.MinerProperty {
+	return shared.MinerProperty{
+		Type:    propertyType,
+		Label:   shared.MinerPropertyLabel{Name: label},
+		Content: shared.MinerPropertyContent{Format: shared.FormatJson, Value: value},
+	}
+}
+
+func TestGrantExposure(t *testing.T) {
+	tests := []struct {
+		name  string
+		grant types.Grant
+		want  []string
+	}{
+		{
+			name: "all users read",
+			grant: types.Grant{
+				Grantee: &types.Grantee{
+					Type: types.TypeGroup,
+					URI:  aws.String("http://acs.amazonaws.com/groups/global/AllUsers"),
+				},
+				Permission: types.PermissionRead,
+			},
+			want: []string{exposurePublicRead},
+		},
+		{
+			name: "authenticated users full control",
+			grant: types.Grant{
+				Grantee: &types.Grantee{
+					Type: types.TypeGroup,
+					URI:  aws.String("http://acs.amazonaws.com/groups/global/AuthenticatedUsers"),
+				},
+				Permission: types.PermissionFullControl,
+			},
+			want: []string{exposurePublicRead, exposurePublicWrite},
+		},
+		{
+			name: "log delivery group",
+			grant: types.Grant{
+				Grantee: &types.Grantee{
+					Type: types.TypeGroup,
+					URI:  aws.String("http://acs.amazonaws.com/groups/s3/LogDelivery"),
+				},
+				Permission: types.PermissionWrite,
+			},
+			want: []string{},
+		},
+		{
+			name: "bucket owner",
+			grant: types.Grant{
+				Grantee:    &types.Grantee{Type: types.TypeCanonicalUser, ID: aws.String(testOwnerId)},
+				Permission: types.PermissionFullControl,
+			},
+			want: []string{},
+		},
+		{
+			name: "other canonical user full control",
+			grant: types.Grant{
+				Grantee:    &types.Grantee{Type: types.TypeCanonicalUser, ID: aws.String("other-id")},
+				Permission: types.PermissionFullControl,
+			},
+			want: []string{exposureCrossAccount},
+		},
+		{name: "no grantee", grant: types.Grant{Permission: types.PermissionRead}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, reason := range grantExposure(tt.grant, testOwnerId) {
+				got = append(got, reason.Exposure)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("grantExposure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatementExposure(t *testing.T) {
+	notPublic := false
+	tests := []struct {
+		name           string
+		statement      string
+		policyIsPublic *bool
+		want           []string
+	}{
+		{
+			name:      "public read",
+			statement: `{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject"}`,
+			want:      []string{exposurePublicRead},
+		},
+		{
+			name:      "public read write",
+			statement: `{"Effect": "Allow", "Principal": {"AWS": "*"}, "Action": "s3:*"}`,
+			want:      []string{exposurePublicRead, exposurePublicWrite},
+		},
+		{
+			name:           "wildcard restricted by conditions",
+			statement:      `{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject"}`,
+			policyIsPublic: &notPublic,
+			want:           []string{},
+		},
+		{
+			name:      "deny",
+			statement: `{"Effect": "Deny", "Principal": "*", "Action": "s3:*"}`,
+			want:      []string{},
+		},
+		{
+			name:      "other account",
+			statement: `{"Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::444455556666:root"}, "Action": "s3:PutObject"}`,
+			want:      []string{exposureCrossAccount},
+		},
+		{
+			name:      "same account",
+			statement: `{"Effect": "Allow", "Principal": {"AWS": "111122223333"}, "Action": "s3:GetObject"}`,
+			want:      []string{},
+		},
+		{
+			name:      "service principal",
+			statement: `{"Effect": "Allow", "Principal": {"Service": "logging.s3.amazonaws.com"}, "Action": "s3:PutObject"}`,
+			want:      []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var statement utils.PolicyStatement
+			if err := json.Unmarshal([]byte(tt.statement), &statement); err != nil {
+				t.Fatalf("statement: %v", err)
+			}
+			got := []string{}
+			for _, reason := range statementExposure(statement, testAccountId, tt.policyIsPublic) {
+				got = append(got, reason.Exposure)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("statementExposure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublicExposureVerdict(t *testing.T) {
+	allUsersRead := `{"Grantee": {"Type": "Group", "URI": "http://acs.amazonaws.com/groups/global/AllUsers"}, "Permission": "READ"}`
+	publicPolicy := `{"Statement": {"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject"}}`
+
+	tests := []struct {
+		name       string
+		properties []shared.MinerProperty
+		account    blockPublicAccess
+		want       string
+	}{
+		{name: "nothing granted", want: exposurePrivate},
+		{
+			name: "public acl",
+			properties: []shared.MinerProperty{
+				testProperty(acl, "Owner", testOwnerId),
+				testProperty(acl, "READ|AllUsers", allUsersRead),
+			},
+			want: exposurePublicRead,
+		},
+		{
+			name: "public acl ignored by account",
+			properties: []shared.MinerProperty{
+				testProperty(acl, "Owner", testOwnerId),
+				testProperty(acl, "READ|AllUsers", allUsersRead),
+			},
+			account: blockPublicAccess{"IgnorePublicAcls": true},
+			want:    exposurePrivate,
+		},
+		{
+			name: "public acl disabled by object ownership",
+			properties: []shared.MinerProperty{
+				testProperty(acl, "Owner", testOwnerId),
+				testProperty(acl, "READ|AllUsers", allUsersRead),
+				testProperty(
+					ownershipControl,
+					"OwnershipControls",
+					`{"Rules": [{"ObjectOwnership": "BucketOwnerEnforced"}]}`,
+				),
+			},
+			want: exposurePrivate,
+		},
+		{
+			name:       "public policy",
+			properties: []shared.MinerProperty{testProperty(policy, "Policy", publicPolicy)},
+			want:       exposurePublicRead,
+		},
+		{
+			name: "public policy restricted by bucket",
+			properties: []shared.MinerProperty{
+				testProperty(policy, "Policy", publicPolicy),
+				testProperty(publicAccessBlock, "RestrictPublicBuckets", "true"),
+			},
+			want: exposurePrivate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := shared.MinerResource{Identifier: "bucket", Properties: tt.properties}
+			property, err := publicExposure(
+				resource,
+				accountExposure{accountId: testAccountId, blockPublicAccess: tt.account},
+			)
+			if err != nil {
+				t.Fatalf("publicExposure() error = %v", err)
+			}
+			var exposure bucketExposure
+			if err := json.Unmarshal([]byte(property.Content.Value), &exposure); err != nil {
+				t.Fatalf("exposure content: %v", err)
+			}
+			if exposure.Verdict != tt.want {
+				t.Errorf("publicExposure() verdict = %s, want %s", exposure.Verdict, tt.want)
+			}
+		})
+	}
+}
+
+func TestArnAccountId(t *testing.T) {
+	tests := map[string]string{
+		"111122223333":                          "111122223333",
+		"arn:aws:iam::444455556666:role/reader": "444455556666",
+		"arn:aws-cn:iam::444455556666:root":     "444455556666",
+		"arn:aws:s3:::bucket":                   "",
+		"*":                                     "",
+		"1234":                                  "",
+	}
+	for principal, want := range tests {
+		if got := arnAccountId(principal); got != want {
+			t.Errorf("arnAccountId(%q) = %q, want %q", principal, got, want)
+		}
+	}
+}
@@ -12,6 +12,8 @@ import (
 
 // intelligentTieringProp is a crawler for fetching s3 IntelligentTiering properties
 type intelligentTieringMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *s3Client
 	configuration *s3.ListBucketIntelligentTieringConfigurationsOutput
@@ -81,7 +83,7 @@ func (it *intelligentTieringMiner) Generate(dummy utils.CacheInfo) ([]shared.Min
 					Format: shared.FormatJson,
 				},
 			}
-			if err := property.FormatContentValue(config); err != nil {
+			if err := property.FormatContentValue(utils.CanonicalContent(config)); err != nil {
 				return nil, fmt.Errorf("generate intelligentTiering: %w", err)
 			}
 			properties = append(properties, property)
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"slices"
+
 	"github.com/liuminhaw/mm-plugins/utils"
 )
 
@@ -11,6 +13,9 @@ var propsConstructors = []utils.PropsCrawlerConstructor{
 	func(client utils.Client) (utils.PropsCrawler, error) {
 		return newAccelerateMiner(client, accelerateConfig)
 	},
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newAccessPointMiner(client, accessPoint)
+	},
 	func(client utils.Client) (utils.PropsCrawler, error) {
 		return newAnalyticsMiner(client, analyticsConfig)
 	},
@@ -41,6 +46,9 @@ var propsConstructors = []utils.PropsCrawlerConstructor{
 	func(client utils.Client) (utils.PropsCrawler, error) {
 		return newNotificationMiner(client, notification)
 	},
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newObjectLockMiner(client, objectLock)
+	},
 	func(client utils.Client) (utils.PropsCrawler, error) {
 		return newOwnershipControlMiner(client, ownershipControl)
 	},
@@ -50,6 +58,9 @@ var propsConstructors = []utils.PropsCrawlerConstructor{
 	func(client utils.Client) (utils.PropsCrawler, error) {
 		return newPolicyStatusMiner(client, policyStatus)
 	},
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newPublicAccessBlockMiner(client, publicAccessBlock)
+	},
 	func(client utils.Client) (utils.PropsCrawler, error) {
 		return newReplicationMiner(client, replication)
 	},
@@ -66,3 +77,51 @@ var propsConstructors = []utils.PropsCrawlerConstructor{
 		return newWebsiteMiner(client, website)
 	},
 }
+
+// objectStatsConstructor builds the opt-in object statistics crawler
+var objectStatsConstructor utils.PropsCrawlerConstructor = func(
+	client utils.Client,
+) (utils.PropsCrawler, error) {
+	return newObjectStatsMiner(client, objectStatsProperty)
+}
+
+// bucketPropsConstructors returns the bucket property crawlers of the equipment selection,
+// with the object statistics crawler when enabled, wrapped for s3-compatible storage
+func bucketPropsConstructors(
+	statsConfig objectStatsConfig,
+	compatible bool,
+) []utils.PropsCrawlerConstructor {
+	constructors := slices.Clone(propsConstructors)
+	if statsConfig.enabled {
+		constructors = append(constructors, objectStatsConstructor)
+	}
+	if compatible {
+		constructors = compatibleConstructors(constructors)
+	}
+
+	return constructors
+}
+
+var accountPropsConstructors = []utils.PropsCrawlerConstructor{
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newAccountPublicAccessBlockMiner(client, accountPublicAccessBlock)
+	},
+}
+
+var multiRegionAccessPointPropsConstructors = []utils.PropsCrawlerConstructor{
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newMultiRegionAccessPointDetailMiner(client, multiRegionAccessPointDetail)
+	},
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newMultiRegionAccessPointPublicAccessBlockMiner(
+			client,
+			multiRegionAccessPointPublicAccessBlock,
+		)
+	},
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newMultiRegionAccessPointPolicyMiner(client, multiRegionAccessPointPolicy)
+	},
+	func(client utils.Client) (utils.PropsCrawler, error) {
+		return newMultiRegionAccessPointPolicyStatusMiner(client, multiRegionAccessPointPolicyStatus)
+	},
+}
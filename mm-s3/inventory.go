@@ -11,6 +11,8 @@ import (
 )
 
 type inventoryMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *s3Client
 	configuration *s3.ListBucketInventoryConfigurationsOutput
@@ -72,7 +74,7 @@ func (i *inventoryMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerProperty
 					Format: shared.FormatJson,
 				},
 			}
-			if err := property.FormatContentValue(config); err != nil {
+			if err := property.FormatContentValue(utils.CanonicalContent(config)); err != nil {
 				return nil, fmt.Errorf("generate bucket inventory: %w", err)
 			}
 
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/aws/smithy-go"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+type kmsKeyPolicySummary struct {
+	Statements int
+	// Principals allowed by the key policy, as <type>:<principal>
+	AllowedPrincipals []string
+	WildcardPrincipal bool
+}
+
+// kmsKeyContent is a kms key reference resolved to the key details
+type kmsKeyContent struct {
+	KeyArn               string
+	Aliases              []string
+	KeyManager           types.KeyManagerType
+	KeyState             types.KeyState
+	KeyRotationEnabled   *bool
+	RotationPeriodInDays *int32
+	Policy               *kmsKeyPolicySummary
+	// Errors of the lookups that could not be made, e.g. for keys of other accounts
+	Errors []string
+}
+
+// kmsKeyCache holds the kms keys resolved during the run, for a key shared by buckets to be
+// looked up once
+type kmsKeyCache struct {
+	// arns maps the key references, <region>|<key id, arn or alias>, to the resolved key arn
+	arns map[string]string
+	// keys holds the resolved keys by arn, or by reference when the key could not be described
+	keys map[string]kmsKeyContent
+}
+
+func newKmsKeyCache() *kmsKeyCache {
+	return &kmsKeyCache{arns: map[string]string{}, keys: map[string]kmsKeyContent{}}
+}
+
+// get returns the cached key of the reference, a nil cache holds no key
+func (c *kmsKeyCache) get(reference string) (kmsKeyContent, bool) {
+	if c == nil {
+		return kmsKeyContent{}, false
+	}
+	if arn, ok := c.arns[reference]; ok {
+		reference = arn
+	}
+	key, ok := c.keys[reference]
+	return key, ok
+}
+
+// getArn returns the cached key of the arn, recording the reference resolved to it
+func (c *kmsKeyCache) getArn(reference, arn string) (kmsKeyContent, bool) {
+	if c == nil {
+		return kmsKeyContent{}, false
+	}
+	key, ok := c.keys[arn]
+	if ok {
+		c.arns[reference] = arn
+	}
+	return key, ok
+}
+
+func (c *kmsKeyCache) add(reference string, key kmsKeyContent) {
+	if c == nil {
+		return
+	}
+	if key.KeyArn == "" {
+		c.keys[reference] = key
+		return
+	}
+	c.arns[reference] = key.KeyArn
+	c.keys[key.KeyArn] = key
+}
+
+// resolveKmsKey resolves a key id, key arn, alias name or alias arn with the key details,
+// rotation status and key policy summary. Failed lookups are recorded in the content. Keys
+// are cached in keys, a key already resolved through another reference is only described
+// to find its arn.
+func resolveKmsKey(client *kms.Client, keys *kmsKeyCache, keyId string) kmsKeyContent {
+	aliases := []string{}
+	if client == nil {
+		return kmsKeyContent{Aliases: aliases, Errors: []string{}}
+	}
+	if strings.HasPrefix(keyId, "alias/") || strings.Contains(keyId, ":alias/") {
+		aliases = append(aliases, keyId[strings.Index(keyId, "alias/"):])
+	}
+
+	reference := client.Options().Region + valueSeparator + keyId
+	key, ok := keys.get(reference)
+	if !ok {
+		key = describeKmsKey(client, keys, reference, keyId)
+	}
+
+	return key.withAliases(aliases)
+}
+
+// describeKmsKey looks up the key of the reference and adds it to keys
+func describeKmsKey(
+	client *kms.Client,
+	keys *kmsKeyCache,
+	reference string,
+	keyId string,
+) kmsKeyContent {
+	content := kmsKeyContent{Aliases: []string{}, Errors: []string{}}
+
+	// Key arns of another region are looked up in the key region
+	optFns := []func(*kms.Options){}
+	if parts := strings.Split(keyId, ":"); len(parts) >= 6 && parts[0] == "arn" {
+		optFns = append(optFns, func(o *kms.Options) { o.Region = parts[3] })
+	}
+
+	describe, err := client.DescribeKey(
+		context.Background(),
+		&kms.DescribeKeyInput{KeyId: aws.String(keyId)},
+		optFns...,
+	)
+	if err != nil {
+		content.Errors = append(content.Errors, lookupError("DescribeKey", err))
+		keys.add(reference, content)
+		return content
+	}
+	metadata := describe.KeyMetadata
+	if key, ok := keys.getArn(reference, aws.ToString(metadata.Arn)); ok {
+		return key
+	}
+	content.KeyArn = aws.ToString(metadata.Arn)
+	content.KeyManager = metadata.KeyManager
+	content.KeyState = metadata.KeyState
+
+	rotation, err := client.GetKeyRotationStatus(
+		context.Background(),
+		&kms.GetKeyRotationStatusInput{KeyId: metadata.Arn},
+		optFns...,
+	)
+	if err != nil {
+		content.Errors = append(content.Errors, lookupError("GetKeyRotationStatus", err))
+	} else {
+		content.KeyRotationEnabled = aws.Bool(rotation.KeyRotationEnabled)
+		content.RotationPeriodInDays = rotation.RotationPeriodInDays
+	}
+
+	paginator := kms.NewListAliasesPaginator(
+		client,
+		&kms.ListAliasesInput{KeyId: metadata.KeyId},
+	)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background(), optFns...)
+		if err != nil {
+			content.Errors = append(content.Errors, lookupError("ListAliases", err))
+			break
+		}
+		for _, alias := range page.Aliases {
+			content.Aliases = append(content.Aliases, aws.ToString(alias.AliasName))
+		}
+	}
+
+	policy, err := client.GetKeyPolicy(
+		context.Background(),
+		&kms.GetKeyPolicyInput{KeyId: metadata.KeyId, PolicyName: aws.String("default")},
+		optFns...,
+	)
+	if err != nil {
+		content.Errors = append(content.Errors, lookupError("GetKeyPolicy", err))
+	} else if summary, err := summarizeKeyPolicy(aws.ToString(policy.Policy)); err != nil {
+		content.Errors = append(content.Errors, lookupError("GetKeyPolicy", err))
+	} else {
+		content.Policy = &summary
+	}
+
+	keys.add(reference, content)
+	return content
+}
+
+// withAliases returns a copy of the key with the aliases it was referenced by, which the
+// aliases listing misses for keys of other accounts, in sorted order
+func (k kmsKeyContent) withAliases(aliases []string) kmsKeyContent {
+	k.Aliases = append(slices.Clone(k.Aliases), aliases...)
+	slices.Sort(k.Aliases)
+	k.Aliases = slices.Compact(k.Aliases)
+	return k
+}
+
+// summarizeKeyPolicy lists the principals allowed by the key policy statements
+func summarizeKeyPolicy(policy string) (kmsKeyPolicySummary, error) {
+	document, err := utils.ParsePolicyDocument(policy)
+	if err != nil {
+		return kmsKeyPolicySummary{}, fmt.Errorf("summarizeKeyPolicy: %w", err)
+	}
+
+	summary := kmsKeyPolicySummary{
+		Statements:        len(document.Statement),
+		AllowedPrincipals: []string{},
+	}
+	for _, statement := range document.Statement {
+		if statement.Effect != "Allow" {
+			continue
+		}
+		if len(statement.NotPrincipal) > 0 {
+			summary.WildcardPrincipal = true
+		}
+		for _, principalType := range statement.Principal.Types() {
+			for _, principal := range statement.Principal[principalType] {
+				if principal == "*" {
+					summary.WildcardPrincipal = true
+				}
+				summary.AllowedPrincipals = append(
+					summary.AllowedPrincipals,
+					principalType+":"+principal,
+				)
+			}
+		}
+	}
+	slices.Sort(summary.AllowedPrincipals)
+	summary.AllowedPrincipals = slices.Compact(summary.AllowedPrincipals)
+
+	return summary, nil
+}
+
+// lookupError describes a failed lookup by its api error code, the error message holds the
+// request id which changes on every run
+func lookupError(operation string, err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return operation + ": " + apiErr.ErrorCode()
+	}
+	return operation + ": failed"
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+func TestResolveKmsKeyCache(t *testing.T) {
+	keyArn := "arn:aws:kms:us-east-1:111122223333:key/data"
+	calls := map[string]int{}
+	cfg := testAwsConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		operation := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "TrentService.")
+		calls[operation]++
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		switch operation {
+		case "DescribeKey":
+			fmt.Fprintf(w, `{"KeyMetadata": {"Arn": %q, "KeyId": "data", "KeyManager": "CUSTOMER", "KeyState": "Enabled"}}`, keyArn)
+		case "GetKeyRotationStatus":
+			fmt.Fprint(w, `{"KeyRotationEnabled": true}`)
+		case "ListAliases":
+			fmt.Fprint(w, `{"Aliases": [{"AliasName": "alias/data"}]}`)
+		case "GetKeyPolicy":
+			fmt.Fprint(w, `{"Policy": "{\"Statement\": []}"}`)
+		}
+	})
+	client := kms.NewFromConfig(cfg)
+	keys := newKmsKeyCache()
+
+	// Two buckets sharing the key by alias, a third referencing it by arn
+	references := []string{"alias/data", "alias/data", keyArn}
+	for _, reference := range references {
+		key := resolveKmsKey(client, keys, reference)
+		if key.KeyArn != keyArn || !slices.Equal(key.Aliases, []string{"alias/data"}) {
+			t.Errorf("resolveKmsKey(%s) = %+v", reference, key)
+		}
+	}
+
+	want := map[string]int{
+		"DescribeKey":          2,
+		"GetKeyRotationStatus": 1,
+		"ListAliases":          1,
+		"GetKeyPolicy":         1,
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("kms calls = %v, want %v", calls, want)
+	}
+}
@@ -13,6 +13,8 @@ import (
 )
 
 type lifecycleMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *s3Client
 	configuration *s3.GetBucketLifecycleConfigurationOutput
@@ -77,7 +79,7 @@ func (l *lifecycleMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerProperty
 				Format: shared.FormatJson,
 			},
 		}
-		if err := property.FormatContentValue(rule); err != nil {
+		if err := property.FormatContentValue(utils.CanonicalContent(rule)); err != nil {
 			return nil, fmt.Errorf("generate lifecycleProp: %w", err)
 		}
 
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/liuminhaw/mist-miner/shared"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+// lifecycleRuleContent is the canonical content of a mined lifecycle rule
+type lifecycleRuleContent struct {
+	ID     string
+	Status types.ExpirationStatus
+	// Deprecated rule level prefix
+	Prefix *string
+	Filter *struct {
+		Prefix                *string
+		Tag                   *types.Tag
+		ObjectSizeGreaterThan *int64
+		ObjectSizeLessThan    *int64
+		And                   *types.LifecycleRuleAndOperator
+	}
+	Expiration                     *types.LifecycleExpiration
+	Transitions                    []types.Transition
+	NoncurrentVersionExpiration    *types.NoncurrentVersionExpiration
+	NoncurrentVersionTransitions   []types.NoncurrentVersionTransition
+	AbortIncompleteMultipartUpload *types.AbortIncompleteMultipartUpload
+}
+
+// lifecycleScope is the set of objects a rule applies to
+type lifecycleScope struct {
+	Prefix                string
+	Tags                  map[string]string
+	ObjectSizeGreaterThan *int64
+	ObjectSizeLessThan    *int64
+}
+
+// wholeBucket reports whether the scope applies to every object of the bucket
+func (s lifecycleScope) wholeBucket() bool {
+	return s.Prefix == "" && len(s.Tags) == 0 &&
+		s.ObjectSizeGreaterThan == nil && s.ObjectSizeLessThan == nil
+}
+
+// overlaps reports whether an object can be in both scopes: one prefix contains the other,
+// tags do not require different values for the same key and size ranges intersect
+func (s lifecycleScope) overlaps(other lifecycleScope) bool {
+	if !strings.HasPrefix(s.Prefix, other.Prefix) && !strings.HasPrefix(other.Prefix, s.Prefix) {
+		return false
+	}
+	for key, value := range s.Tags {
+		if otherValue, ok := other.Tags[key]; ok && otherValue != value {
+			return false
+		}
+	}
+
+	lower := max(aws.ToInt64(s.ObjectSizeGreaterThan), aws.ToInt64(other.ObjectSizeGreaterThan))
+	for _, upper := range []*int64{s.ObjectSizeLessThan, other.ObjectSizeLessThan} {
+		if upper != nil && *upper <= lower+1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+type lifecycleRuleCoverage struct {
+	Rule    string
+	Status  types.ExpirationStatus
+	Scope   lifecycleScope
+	Actions []string
+	// Enabled rules applying to some of the same objects
+	Overlaps  []string
+	Conflicts []string
+}
+
+type lifecycleFinding struct {
+	Issue  string
+	Detail string
+}
+
+type lifecycleAnalysis struct {
+	Versioning string
+	Rules      []lifecycleRuleCoverage
+	Findings   []lifecycleFinding
+}
+
+// lifecycleAnalysisProperty evaluates the coverage of the mined lifecycle rules of a bucket,
+// the overlaps and conflicts between enabled rules, and the cost issues of noncurrent versions
+// never expiring on versioned buckets and incomplete multipart uploads never aborted by the
+// lifecycle rules of the bucket.
+func lifecycleAnalysisProperty(resource shared.MinerResource) (shared.MinerProperty, error) {
+	analysis := lifecycleAnalysis{
+		Rules:    []lifecycleRuleCoverage{},
+		Findings: []lifecycleFinding{},
+	}
+
+	rules := []lifecycleRuleContent{}
+	for _, property := range resource.Properties {
+		switch property.Type {
+		case lifecycle:
+			var rule lifecycleRuleContent
+			if err := json.Unmarshal([]byte(property.Content.Value), &rule); err != nil {
+				return shared.MinerProperty{}, fmt.Errorf("lifecycleAnalysisProperty: %w", err)
+			}
+			rules = append(rules, rule)
+		case versioning:
+			var status types.VersioningConfiguration
+			if err := json.Unmarshal([]byte(property.Content.Value), &status); err != nil {
+				return shared.MinerProperty{}, fmt.Errorf("lifecycleAnalysisProperty: %w", err)
+			}
+			analysis.Versioning = string(status.Status)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+
+	scopes := make([]lifecycleScope, len(rules))
+	for i, rule := range rules {
+		scopes[i] = rule.scope()
+	}
+
+	noncurrentExpiration, abortMultipart := []string{}, []string{}
+	wholeNoncurrentExpiration, wholeAbortMultipart := false, false
+	for i, rule := range rules {
+		coverage := lifecycleRuleCoverage{
+			Rule:      rule.ID,
+			Status:    rule.Status,
+			Scope:     scopes[i],
+			Actions:   rule.actions(),
+			Overlaps:  []string{},
+			Conflicts: []string{},
+		}
+		if rule.Status != types.ExpirationStatusEnabled {
+			analysis.Rules = append(analysis.Rules, coverage)
+			continue
+		}
+
+		for j, other := range rules {
+			if i == j || other.Status != types.ExpirationStatusEnabled ||
+				!scopes[i].overlaps(scopes[j]) {
+				continue
+			}
+			coverage.Overlaps = append(coverage.Overlaps, other.ID)
+			conflicts := rule.conflicts(other)
+			coverage.Conflicts = append(coverage.Conflicts, conflicts...)
+			// Each conflicting pair is reported once
+			if i > j {
+				continue
+			}
+			for _, conflict := range conflicts {
+				analysis.Findings = append(analysis.Findings, lifecycleFinding{
+					Issue:  lifecycleRuleConflict,
+					Detail: fmt.Sprintf("rule %s: %s", rule.ID, conflict),
+				})
+			}
+		}
+
+		if rule.NoncurrentVersionExpiration != nil {
+			noncurrentExpiration = append(noncurrentExpiration, rule.ID)
+			wholeNoncurrentExpiration = wholeNoncurrentExpiration || scopes[i].wholeBucket()
+		}
+		if rule.AbortIncompleteMultipartUpload != nil {
+			abortMultipart = append(abortMultipart, rule.ID)
+			wholeAbortMultipart = wholeAbortMultipart || scopes[i].wholeBucket()
+		}
+		analysis.Rules = append(analysis.Rules, coverage)
+	}
+
+	if analysis.Versioning != "" && !wholeNoncurrentExpiration {
+		analysis.Findings = append(analysis.Findings, lifecycleFinding{
+			Issue: lifecycleNoncurrentNeverExpire,
+			Detail: partialCoverageDetail(
+				fmt.Sprintf(
+					"noncurrent versions are never expired while versioning is %s",
+					analysis.Versioning,
+				),
+				noncurrentExpiration,
+			),
+		})
+	}
+	// Without lifecycle configuration the bucket is not reported, aborting uploads is only
+	// checked along with the rules already configured
+	if len(rules) > 0 && !wholeAbortMultipart {
+		analysis.Findings = append(analysis.Findings, lifecycleFinding{
+			Issue: lifecycleMultipartNotAborted,
+			Detail: partialCoverageDetail(
+				"incomplete multipart uploads are never aborted",
+				abortMultipart,
+			),
+		})
+	}
+
+	property := shared.MinerProperty{
+		Type: lifecycleAnalysisType,
+		Label: shared.MinerPropertyLabel{
+			Name:   "LifecycleAnalysis",
+			Unique: true,
+		},
+		Content: shared.MinerPropertyContent{
+			Format: shared.FormatJson,
+		},
+	}
+	if err := property.FormatContentValue(utils.CanonicalContent(analysis)); err != nil {
+		return shared.MinerProperty{}, fmt.Errorf("lifecycleAnalysisProperty: %w", err)
+	}
+
+	return property, nil
+}
+
+// partialCoverageDetail completes an issue detail with the rules covering part of the bucket
+func partialCoverageDetail(detail string, rules []string) string {
+	if len(rules) > 0 {
+		detail += fmt.Sprintf(", only partially covered by rules %s", strings.Join(rules, ", "))
+	}
+
+	return detail
+}
+
+// scope gets the objects the rule applies to from its filter, or its deprecated prefix
+func (r lifecycleRuleContent) scope() lifecycleScope {
+	scope := lifecycleScope{Prefix: aws.ToString(r.Prefix), Tags: map[string]string{}}
+	if r.Filter == nil {
+		return scope
+	}
+
+	tags := []types.Tag{}
+	switch {
+	case r.Filter.And != nil:
+		scope.Prefix = aws.ToString(r.Filter.And.Prefix)
+		scope.ObjectSizeGreaterThan = r.Filter.And.ObjectSizeGreaterThan
+		scope.ObjectSizeLessThan = r.Filter.And.ObjectSizeLessThan
+		tags = r.Filter.And.Tags
+	case r.Filter.Tag != nil:
+		tags = append(tags, *r.Filter.Tag)
+	default:
+		scope.Prefix = aws.ToString(r.Filter.Prefix)
+		scope.ObjectSizeGreaterThan = r.Filter.ObjectSizeGreaterThan
+		scope.ObjectSizeLessThan = r.Filter.ObjectSizeLessThan
+	}
+	for _, tag := range tags {
+		scope.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+
+	return scope
+}
+
+// actions lists the lifecycle actions defined by the rule
+func (r lifecycleRuleContent) actions() []string {
+	actions := []string{}
+	if r.Expiration != nil {
+		actions = append(actions, "Expiration")
+	}
+	for _, transition := range r.Transitions {
+		actions = append(actions, fmt.Sprintf("Transition %s", transition.StorageClass))
+	}
+	if r.NoncurrentVersionExpiration != nil {
+		actions = append(actions, "NoncurrentVersionExpiration")
+	}
+	for _, transition := range r.NoncurrentVersionTransitions {
+		actions = append(actions, fmt.Sprintf("NoncurrentVersionTransition %s", transition.StorageClass))
+	}
+	if r.AbortIncompleteMultipartUpload != nil {
+		actions = append(actions, "AbortIncompleteMultipartUpload")
+	}
+
+	return actions
+}
+
+// conflicts describes the actions both rules define with different settings, s3 then applies
+// the one of lowest cost, which may not be the intended one
+func (r lifecycleRuleContent) conflicts(other lifecycleRuleContent) []string {
+	conflicts := []string{}
+	conflict := func(action string, value, otherValue any) {
+		valueJson, _ := json.Marshal(utils.CanonicalContent(value))
+		otherJson, _ := json.Marshal(utils.CanonicalContent(otherValue))
+		if string(valueJson) != string(otherJson) {
+			conflicts = append(conflicts, fmt.Sprintf(
+				"%s %s differs from rule %s %s", action, valueJson, other.ID, otherJson,
+			))
+		}
+	}
+
+	if r.Expiration != nil && other.Expiration != nil {
+		conflict("Expiration", r.Expiration, other.Expiration)
+	}
+	if r.NoncurrentVersionExpiration != nil && other.NoncurrentVersionExpiration != nil {
+		conflict("NoncurrentVersionExpiration", r.NoncurrentVersionExpiration, other.NoncurrentVersionExpiration)
+	}
+	if r.AbortIncompleteMultipartUpload != nil && other.AbortIncompleteMultipartUpload != nil {
+		conflict("AbortIncompleteMultipartUpload", r.AbortIncompleteMultipartUpload, other.AbortIncompleteMultipartUpload)
+	}
+	for _, transition := range r.Transitions {
+		for _, otherTransition := range other.Transitions {
+			if transition.StorageClass == otherTransition.StorageClass {
+				conflict(fmt.Sprintf("Transition %s", transition.StorageClass), transition, otherTransition)
+			}
+		}
+	}
+
+	return conflicts
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/liuminhaw/mist-miner/shared"
+)
+
+func TestLifecycleAnalysis(t *testing.T) {
+	expireLogs := `{"ID": "expire-logs", "Status": "Enabled", "Filter": {"Prefix": "logs/"},
+		"Expiration": {"Days": 30}}`
+	abortAll := `{"ID": "abort-all", "Status": "Enabled", "Filter": {},
+		"AbortIncompleteMultipartUpload": {"DaysAfterInitiation": 7}}`
+
+	tests := []struct {
+		name          string
+		properties    []shared.MinerProperty
+		wantOverlaps  map[string][]string
+		wantConflicts map[string][]string
+		wantFindings  []string
+	}{
+		{name: "no lifecycle rules", wantFindings: []string{}},
+		{
+			name: "versioning without lifecycle rules",
+			properties: []shared.MinerProperty{
+				testProperty(versioning, "Versioning", `{"Status": "Enabled"}`),
+			},
+			wantFindings: []string{lifecycleNoncurrentNeverExpire},
+		},
+		{
+			name:         "multipart uploads not aborted",
+			properties:   []shared.MinerProperty{testProperty(lifecycle, "expire-logs", expireLogs)},
+			wantOverlaps: map[string][]string{"expire-logs": {}},
+			wantFindings: []string{lifecycleMultipartNotAborted},
+		},
+		{
+			name: "conflicting expirations",
+			properties: []shared.MinerProperty{
+				testProperty(lifecycle, "abort-all", abortAll),
+				testProperty(lifecycle, "expire-logs", expireLogs),
+				testProperty(lifecycle, "expire-app-logs", `{"ID": "expire-app-logs",
+					"Status": "Enabled", "Filter": {"Prefix": "logs/app/"}, "Expiration": {"Days": 90}}`),
+			},
+			wantOverlaps: map[string][]string{
+				"abort-all":       {"expire-app-logs", "expire-logs"},
+				"expire-app-logs": {"abort-all", "expire-logs"},
+				"expire-logs":     {"abort-all", "expire-app-logs"},
+			},
+			wantConflicts: map[string][]string{
+				"expire-app-logs": {`Expiration {"Days":90} differs from rule expire-logs {"Days":30}`},
+				"expire-logs":     {`Expiration {"Days":30} differs from rule expire-app-logs {"Days":90}`},
+			},
+			wantFindings: []string{lifecycleRuleConflict},
+		},
+		{
+			name: "disjoint prefixes and disabled rules",
+			properties: []shared.MinerProperty{
+				testProperty(lifecycle, "abort-all", abortAll),
+				testProperty(lifecycle, "expire-logs", expireLogs),
+				testProperty(lifecycle, "expire-tmp", `{"ID": "expire-tmp", "Status": "Enabled",
+					"Prefix": "tmp/", "Expiration": {"Days": 1}}`),
+				testProperty(lifecycle, "expire-all", `{"ID": "expire-all", "Status": "Disabled",
+					"Filter": {}, "Expiration": {"Days": 365}}`),
+			},
+			wantOverlaps: map[string][]string{
+				"abort-all":   {"expire-logs", "expire-tmp"},
+				"expire-all":  {},
+				"expire-logs": {"abort-all"},
+				"expire-tmp":  {"abort-all"},
+			},
+			wantFindings: []string{},
+		},
+		{
+			name: "tags and sizes not overlapping",
+			properties: []shared.MinerProperty{
+				testProperty(lifecycle, "small", `{"ID": "small", "Status": "Enabled",
+					"Filter": {"And": {"ObjectSizeLessThan": 1024, "Tags": [{"Key": "tier", "Value": "a"}]}},
+					"Expiration": {"Days": 1}, "AbortIncompleteMultipartUpload": {"DaysAfterInitiation": 1}}`),
+				testProperty(lifecycle, "large", `{"ID": "large", "Status": "Enabled",
+					"Filter": {"ObjectSizeGreaterThan": 2048}, "Expiration": {"Days": 30}}`),
+				testProperty(lifecycle, "tier-b", `{"ID": "tier-b", "Status": "Enabled",
+					"Filter": {"Tag": {"Key": "tier", "Value": "b"}}, "Expiration": {"Days": 7}}`),
+			},
+			wantOverlaps: map[string][]string{
+				"large":  {"tier-b"},
+				"small":  {},
+				"tier-b": {"large"},
+			},
+			wantConflicts: map[string][]string{
+				"large":  {`Expiration {"Days":30} differs from rule tier-b {"Days":7}`},
+				"tier-b": {`Expiration {"Days":7} differs from rule large {"Days":30}`},
+			},
+			wantFindings: []string{lifecycleMultipartNotAborted, lifecycleRuleConflict},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property, err := lifecycleAnalysisProperty(
+				shared.MinerResource{Identifier: "bucket", Properties: tt.properties},
+			)
+			if err != nil {
+				t.Fatalf("lifecycleAnalysisProperty() error = %v", err)
+			}
+			var analysis lifecycleAnalysis
+			if err := json.Unmarshal([]byte(property.Content.Value), &analysis); err != nil {
+				t.Fatalf("analysis content: %v", err)
+			}
+
+			for _, rule := range analysis.Rules {
+				want, ok := tt.wantOverlaps[rule.Rule]
+				if !ok {
+					t.Errorf("unexpected rule %s", rule.Rule)
+					continue
+				}
+				overlaps := append([]string{}, rule.Overlaps...)
+				slices.Sort(overlaps)
+				if !slices.Equal(overlaps, want) {
+					t.Errorf("rule %s overlaps = %v, want %v", rule.Rule, overlaps, want)
+				}
+				if !slices.Equal(rule.Conflicts, tt.wantConflicts[rule.Rule]) {
+					t.Errorf(
+						"rule %s conflicts = %v, want %v",
+						rule.Rule, rule.Conflicts, tt.wantConflicts[rule.Rule],
+					)
+				}
+			}
+			if len(analysis.Rules) != len(tt.wantOverlaps) {
+				t.Errorf("rules = %d, want %d", len(analysis.Rules), len(tt.wantOverlaps))
+			}
+
+			findings := []string{}
+			for _, finding := range analysis.Findings {
+				findings = append(findings, finding.Issue)
+			}
+			slices.Sort(findings)
+			if !slices.Equal(findings, tt.wantFindings) {
+				t.Errorf("findings = %v, want %v", findings, tt.wantFindings)
+			}
+		})
+	}
+}
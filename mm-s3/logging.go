@@ -10,6 +10,8 @@ import (
 )
 
 type loggingMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *s3Client
 	configuration *s3.GetBucketLoggingOutput
@@ -62,7 +64,7 @@ func (l *loggingMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerProperty,
 				Format: shared.FormatJson,
 			},
 		}
-		if err := property.FormatContentValue(l.configuration.LoggingEnabled); err != nil {
+		if err := property.FormatContentValue(utils.CanonicalContent(l.configuration.LoggingEnabled)); err != nil {
 			return nil, fmt.Errorf("generate bucket logging: %w", err)
 		}
 
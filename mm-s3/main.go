@@ -4,12 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
-	"os"
+	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3control"
+	"github.com/aws/smithy-go"
+	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
 	"github.com/liuminhaw/mist-miner/shared"
 	"github.com/liuminhaw/mm-plugins/utils"
@@ -23,67 +27,398 @@ type Miner struct {
 }
 
 func (m Miner) Mine(mineConfig shared.MinerConfig) (shared.MinerResources, error) {
-	log.Printf("Plugin name: %s\n", PLUG_NAME)
+	logger := utils.NewLogger(PLUG_NAME, mineConfig.Equipments)
+	logger.Info("mine")
+	ctx := hclog.WithContext(context.Background(), logger)
+
+	// S3-compatible storage is given by an endpoint, the profile is then optional
+	endpoint, compatible := newCompatibleEndpoint(mineConfig.Auth)
 
 	// Get authentication profile from config
 	awsAuth, err := utils.ConfigAuth(mineConfig)
-	if err != nil {
+	if err != nil && !compatible {
 		return nil, fmt.Errorf("mine: %w", err)
 	}
 
 	resources := shared.MinerResources{}
-	cfg, err := config.LoadDefaultConfig(context.Background(),
-		config.WithSharedConfigProfile(string(awsAuth.Profile)),
-	)
+	var cfg aws.Config
+	if compatible {
+		logger.Info("S3-compatible endpoint", "endpoint", endpoint.url)
+		cfg, err = endpoint.loadConfig(awsAuth.Profile)
+		if err != nil {
+			return nil, fmt.Errorf("mine: %w", err)
+		}
+	} else {
+		cfg, err = config.LoadDefaultConfig(context.Background(),
+			config.WithSharedConfigProfile(string(awsAuth.Profile)),
+		)
+	}
+
+	retry := newRetryPolicies(mineConfig.Equipments)
+	defer retry.logSummary(logger)
+
+	// The run summary counts every api call, sts included
+	run := utils.NewMiningRun(retry.s3, retry.control, retry.kms)
+	cfg = utils.LoggerConfig(run.Config(cfg), logger)
+
+	statsConfig := objectStatsEquipment(ctx, mineConfig.Equipments)
+	// The bucket property crawlers of the run, the required permissions are derived from them
+	constructors := bucketPropsConstructors(statsConfig, compatible)
+
+	// The permissions of aws are checked, s3-compatible storage has its own access control
+	permissionsCheck := utils.NewPermissionsCheck(mineConfig.Equipments)
+	if compatible && permissionsCheck.Enabled() {
+		logger.Info("permissions check not available on S3-compatible endpoint")
+	}
+	var permissionsResource shared.MinerResource
+
+	// Account level settings are mined first, the public exposure evaluation of each
+	// bucket depends on them. S3-compatible storage has no account level settings.
+	var accountId string
+	var account shared.MinerResource
+	partition := utils.PartitionAws
+	if !compatible {
+		// The partition sets the region of the buckets without location constraint
+		accountId, partition, err = utils.CallerIdentity(cfg)
+		if errors.Is(err, utils.ErrNoRegion) {
+			return nil, fmt.Errorf("mine: %w", err)
+		}
+		if err != nil {
+			logger.Warn("failed to get caller identity", utils.ErrorArgs(err)...)
+		}
+		logger.Info("caller identity", "account", accountId, "partition", partition.Name)
+
+		// Permissions are checked before mining, for the missing ones to be told up front
+		if permissionsCheck.Enabled() {
+			start := time.Now()
+			permissions, err := requiredPermissions(constructors, partition)
+			if err != nil {
+				return nil, fmt.Errorf("mine: %w", err)
+			}
+			permissionsResource, err = permissionsCheck.Resource(
+				ctx,
+				cfg,
+				permissions,
+				logger,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("mine: %w", err)
+			}
+			run.AddResourceTypeDuration(utils.RequiredPermissionsResource, time.Since(start))
+		}
+
+		start := time.Now()
+		account, err = mineAccount(retry.control.Config(cfg), accountId, run)
+		if err != nil {
+			var configErr *utils.MMError
+			if errors.As(err, &configErr) {
+				logger.Debug("no properties found", "resource", accountResource)
+			} else {
+				logger.Error(
+					"failed to get properties",
+					append([]any{"resource", accountResource}, utils.ErrorArgs(err)...)...,
+				)
+			}
+		}
+		run.AddResourceTypeDuration(accountResource, time.Since(start))
+	}
+	exposureInfo := accountExposure{
+		accountId:         accountId,
+		blockPublicAccess: newBlockPublicAccess(account, accountPublicAccessBlock),
+	}
+
+	vanished := utils.VanishedEntities{}
+	defer vanished.LogSummary(logger)
 
-	client := s3.NewFromConfig(cfg)
+	client := s3.NewFromConfig(retry.s3.Config(cfg), endpoint.s3Options)
+	regionalClients := newRegionalClients(cfg, retry)
 	bucketsOutput, err := client.ListBuckets(context.Background(), &s3.ListBucketsInput{})
 	if err != nil {
 		return nil, fmt.Errorf("mine: list buckets: %w", err)
 	}
 
 	for _, bucket := range bucketsOutput.Buckets {
-		log.Printf("Bucket: %s\n", *bucket.Name)
+		bucketLogger := logger.With("resource", aws.ToString(bucket.Name))
+		bucketLogger.Info("mine bucket")
+		start := time.Now()
 
-		bucketRegion, err := getBucketRegion(client, *bucket.Name)
-		if err != nil {
-			log.Printf("Failed to get bucket region: %v", err)
-			continue
-		}
-
-		cfg, err := config.LoadDefaultConfig(context.Background(),
-			config.WithSharedConfigProfile(string(awsAuth.Profile)),
-			config.WithRegion(bucketRegion),
-		)
+		// S3-compatible storage buckets share the endpoint client, without s3control and kms
+		var serviceClient *s3Client
+		bucketRegion := endpoint.region
+		if compatible {
+			serviceClient = newS3Client(
+				client,
+				&bucket,
+				nil,
+				accountId,
+				nil,
+				statsConfig,
+				run,
+				nil,
+				partition,
+			)
+		} else {
+			bucketRegion, err = getBucketRegion(ctx, client, *bucket.Name, partition)
+			if err != nil && utils.IsVanished(err) {
+				bucketLogger.Info("vanished during mining", utils.ErrorArgs(err)...)
+				vanished.Add(bucketResourceType)
+				continue
+			}
+			if err != nil {
+				bucketLogger.Error("failed to get bucket region", utils.ErrorArgs(err)...)
+				resources = appendMiningError(ctx, resources, &utils.PropsError{
+					Identifier:   aws.ToString(bucket.Name),
+					PropertyType: location,
+					Err:          err,
+				})
+				continue
+			}
 
-		serviceClient := newS3Client(s3.NewFromConfig(cfg), &bucket)
+			regional := regionalClients.get(bucketRegion)
+			serviceClient = newS3Client(
+				regional.s3,
+				&bucket,
+				regional.control,
+				accountId,
+				regional.kms,
+				statsConfig,
+				run,
+				regionalClients,
+				partition,
+			)
+		}
 		bucketResource, err := utils.GetProperties(
 			serviceClient,
 			aws.ToString(bucket.Name),
 			utils.CacheInfo{Name: location, Id: aws.ToString(bucket.Name), Content: bucketRegion},
-			propsConstructors,
+			constructors,
 		)
 		if err != nil {
 			var configErr *utils.MMError
-			if errors.As(err, &configErr) {
-				log.Printf("No properties in bucket %s found", aws.ToString(bucket.Name))
+			var propsErr *utils.PropsError
+			if errors.As(err, &configErr) && configErr.Code == utils.Vanished {
+				// Buckets deleted after being listed are left out as if never listed
+				vanished.Add(bucketResourceType)
+			} else if errors.As(err, &configErr) {
+				bucketLogger.Debug("no properties found")
+			} else if errors.As(err, &propsErr) {
+				bucketLogger.Error(
+					"failed to get properties",
+					append([]any{"property", propsErr.PropertyType}, utils.ErrorArgs(err)...)...,
+				)
+				resources = appendMiningError(ctx, resources, propsErr)
 			} else {
-				log.Printf("mineResource: failed to get bucket %s properties: %v", aws.ToString(bucket.Name), err)
+				bucketLogger.Error("failed to get properties", utils.ErrorArgs(err)...)
 			}
 		} else {
+			exposure, err := publicExposure(bucketResource, exposureInfo)
+			if err != nil {
+				bucketLogger.Error("failed to evaluate exposure", "error", err)
+			} else {
+				bucketResource.Properties = append(bucketResource.Properties, exposure)
+			}
+			analysis, err := lifecycleAnalysisProperty(bucketResource)
+			if err != nil {
+				bucketLogger.Error("failed to analyze lifecycle", "error", err)
+			} else {
+				bucketResource.Properties = append(bucketResource.Properties, analysis)
+			}
 			bucketResource.Sort()
 			resources = append(resources, bucketResource)
 		}
+		run.AddResourceTypeDuration(bucketResourceType, time.Since(start))
+	}
+
+	// Destination buckets are only known once every bucket is mined
+	if err := replicationChecks(resources, accountId); err != nil {
+		logger.Error("failed to check bucket replications", "error", err)
+	}
+
+	if len(account.Properties) > 0 {
+		account.Sort()
+		resources = append(resources, account)
+	}
+
+	// Multi-region access points are only available in the aws partition
+	if accountId != "" && partition == utils.PartitionAws {
+		start := time.Now()
+		multiRegionAccessPoints, err := mineMultiRegionAccessPoints(
+			ctx,
+			regionalClients,
+			accountId,
+			run,
+		)
+		if err != nil {
+			logger.Error("failed to get multi-region access points", utils.ErrorArgs(err)...)
+		}
+		resources = append(resources, multiRegionAccessPoints...)
+		run.AddResourceTypeDuration(multiRegionAccessPointResource, time.Since(start))
+	}
 
+	if len(permissionsResource.Properties) > 0 {
+		resources = append(resources, permissionsResource)
+	}
+
+	runResource, err := run.Resource(resources)
+	if err != nil {
+		return nil, fmt.Errorf("mine: %w", err)
+	}
+	resources = append(resources, runResource)
+
+	return resources, nil
+}
+
+// objectStatsEquipment reads the opt-in object statistics setting from equipment, falling
+// back to the default object cap on invalid values.
+func objectStatsEquipment(
+	ctx context.Context,
+	equipments []shared.MinerConfigEquipment,
+) objectStatsConfig {
+	logger := hclog.FromContext(ctx)
+	mode := utils.GetEquipAttribute(
+		equipments,
+		utils.EquipmentInfo{
+			TargetType: bucketEquipmentType,
+			TargetName: "objectStats",
+			TargetAttr: "mode",
+			DefaultVal: "Disabled",
+			AcceptVals: []string{"Enabled", "Disabled"},
+		},
+	)
+	logger.Debug("equipment", "objectStatsMode", mode)
+	if mode != "Enabled" {
+		return objectStatsConfig{}
+	}
+
+	value := utils.GetEquipAttribute(
+		equipments,
+		utils.EquipmentInfo{
+			TargetType: bucketEquipmentType,
+			TargetName: "objectStats",
+			TargetAttr: "maxObjects",
+			DefaultVal: strconv.Itoa(defaultObjectStatsCap),
+		},
+	)
+	maxObjects, err := strconv.Atoi(value)
+	if err != nil || maxObjects <= 0 {
+		logger.Warn(
+			"invalid objectStats maxObjects, use default",
+			"maxObjects", value,
+			"default", defaultObjectStatsCap,
+		)
+		maxObjects = defaultObjectStatsCap
+	}
+
+	prefixes := parsePrefixes(utils.GetEquipAttribute(
+		equipments,
+		utils.EquipmentInfo{
+			TargetType: bucketEquipmentType,
+			TargetName: "objectStats",
+			TargetAttr: "prefixes",
+		},
+	))
+	logger.Debug(
+		"equipment",
+		"objectStatsMaxObjects", maxObjects,
+		"objectStatsPrefixes", prefixes,
+	)
+
+	return objectStatsConfig{enabled: true, maxObjects: maxObjects, prefixes: prefixes}
+}
+
+// mineAccount gets the account level s3 settings of the account the profile belongs to
+func mineAccount(
+	cfg aws.Config,
+	accountId string,
+	run *utils.MiningRun,
+) (shared.MinerResource, error) {
+	// Account id is required by s3control, it is not available when sts failed
+	if accountId == "" {
+		return shared.MinerResource{}, &utils.MMError{Category: accountResource, Code: utils.NoConfig}
+	}
+
+	serviceClient := newS3ControlClient(s3control.NewFromConfig(cfg), accountId, run)
+	resource, err := utils.GetProperties(
+		serviceClient,
+		accountResource,
+		utils.CacheInfo{Name: accountResource, Id: accountId},
+		accountPropsConstructors,
+	)
+	if err != nil {
+		return shared.MinerResource{}, fmt.Errorf("mineAccount: %w", err)
+	}
+
+	return resource, nil
+}
+
+// mineMultiRegionAccessPoints gets the account level multi-region access points, each
+// as its own MultiRegionAccessPoint_<name> resource
+func mineMultiRegionAccessPoints(
+	ctx context.Context,
+	clients *regionalClients,
+	accountId string,
+	run *utils.MiningRun,
+) (shared.MinerResources, error) {
+	resources := shared.MinerResources{}
+
+	serviceClient := newS3ControlClient(
+		clients.get(multiRegionAccessPointRegion).control,
+		accountId,
+		run,
+	)
+
+	paginator := s3control.NewListMultiRegionAccessPointsPaginator(
+		serviceClient.client,
+		&s3control.ListMultiRegionAccessPointsInput{AccountId: aws.String(accountId)},
+	)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return resources, fmt.Errorf("mineMultiRegionAccessPoints: %w", err)
+		}
+
+		for _, report := range page.AccessPoints {
+			name := aws.ToString(report.Name)
+			hclog.FromContext(ctx).Info("mine multi-region access point", "name", name)
+
+			content, err := shared.JsonMarshal(utils.CanonicalContent(report))
+			if err != nil {
+				return resources, fmt.Errorf("mineMultiRegionAccessPoints: %w", err)
+			}
+			normalizedContent, err := shared.JsonNormalize(string(content))
+			if err != nil {
+				return resources, fmt.Errorf("mineMultiRegionAccessPoints: %w", err)
+			}
+
+			resource, err := utils.GetProperties(
+				serviceClient,
+				fmt.Sprintf("%s_%s", multiRegionAccessPointResource, name),
+				utils.CacheInfo{
+					Name:    name,
+					Id:      aws.ToString(report.Alias),
+					Content: string(normalizedContent),
+				},
+				multiRegionAccessPointPropsConstructors,
+			)
+			if err != nil {
+				hclog.FromContext(ctx).Error(
+					"failed to get properties",
+					append([]any{"resource", name}, utils.ErrorArgs(err)...)...,
+				)
+				continue
+			}
+			resource.Sort()
+			resources = append(resources, resource)
+		}
 	}
 
 	return resources, nil
 }
 
 func main() {
-	// logger setup for plugin logs
-	log.SetOutput(os.Stderr)
-	log.Println("Starting miner plugin")
+	// logger setup for plugin logs, the level is set from equipment on mining
+	logger := utils.NewLogger(PLUG_NAME, nil)
+	logger.Info("starting miner plugin")
 
 	plugin.Serve(&plugin.ServeConfig{
 		HandshakeConfig: shared.Handshake,
@@ -95,19 +430,87 @@ func main() {
 	)
 }
 
-// getBucketRegion returns the region of the bucket
-func getBucketRegion(client *s3.Client, bucket string) (string, error) {
-	result, err := client.GetBucketLocation(context.Background(), &s3.GetBucketLocationInput{
+// appendMiningError keeps the bucket that could not be read as a resource carrying the
+// MiningError property, to tell it apart from a removed bucket
+func appendMiningError(
+	ctx context.Context,
+	resources shared.MinerResources,
+	propsErr *utils.PropsError,
+) shared.MinerResources {
+	resource, err := propsErr.Resource()
+	if err != nil {
+		hclog.FromContext(ctx).Error(
+			"failed to record mining error",
+			"resource", propsErr.Identifier,
+			"error", err,
+		)
+		return resources
+	}
+
+	return append(resources, resource)
+}
+
+// getBucketRegion returns the region of the bucket from GetBucketLocation, falling back to
+// the x-amz-bucket-region header of HeadBucket when the location is not readable
+func getBucketRegion(
+	ctx context.Context,
+	client *s3.Client,
+	bucket string,
+	partition utils.Partition,
+) (string, error) {
+	result, err := client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
 		Bucket: &bucket,
 	})
 	if err != nil {
-		return "", fmt.Errorf("getBucketRegion: %w", err)
+		var apiErr smithy.APIError
+		if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "AccessDenied" {
+			return "", fmt.Errorf("getBucketRegion: %w", err)
+		}
+
+		region, headErr := headBucketRegion(client, bucket)
+		if headErr != nil {
+			return "", fmt.Errorf("getBucketRegion: %w, %w", err, headErr)
+		}
+		hclog.FromContext(ctx).Info(
+			"location access denied, region from HeadBucket",
+			"resource", bucket,
+			"region", region,
+		)
+		return region, nil
 	}
 
 	region := string(result.LocationConstraint)
-	if region == "" {
-		region = "us-east-1"
+	switch region {
+	case "":
+		// Buckets without location constraint are in the partition default region
+		region = partition.DefaultRegion
+	case "EU":
+		// Legacy location constraint of eu-west-1
+		region = "eu-west-1"
 	}
 
 	return region, nil
 }
+
+// headBucketRegion reads the bucket region header of HeadBucket, which is also returned with
+// access denied and redirect responses
+func headBucketRegion(client *s3.Client, bucket string) (string, error) {
+	output, err := client.HeadBucket(context.Background(), &s3.HeadBucketInput{
+		Bucket: &bucket,
+	})
+	if err == nil && aws.ToString(output.BucketRegion) != "" {
+		return aws.ToString(output.BucketRegion), nil
+	}
+
+	var responseErr *awshttp.ResponseError
+	if errors.As(err, &responseErr) && responseErr.Response != nil {
+		if region := responseErr.Response.Header.Get("x-amz-bucket-region"); region != "" {
+			return region, nil
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("headBucketRegion: %w", err)
+	}
+
+	return "", fmt.Errorf("headBucketRegion: no bucket region header")
+}
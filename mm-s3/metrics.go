@@ -11,6 +11,8 @@ import (
 )
 
 type metricsMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *s3Client
 	configuration *s3.ListBucketMetricsConfigurationsOutput
@@ -72,7 +74,7 @@ func (m *metricsMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerProperty,
 					Format: shared.FormatJson,
 				},
 			}
-			if err := property.FormatContentValue(config); err != nil {
+			if err := property.FormatContentValue(utils.CanonicalContent(config)); err != nil {
 				return nil, fmt.Errorf("generate metrics: %w", err)
 			}
 
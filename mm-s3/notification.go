@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/liuminhaw/mist-miner/shared"
@@ -11,6 +10,8 @@ import (
 )
 
 type notificationMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *s3Client
 	configuration *s3.GetBucketNotificationConfigurationOutput
@@ -58,7 +59,7 @@ func (n *notificationMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerPrope
 	}
 
 	if n.notificationIsEmpty() {
-		log.Println("No notifications configuration found")
+		n.Logger().Debug("no configuration found")
 	} else {
 		property := shared.MinerProperty{
 			Type: notification,
@@ -70,7 +71,7 @@ func (n *notificationMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerPrope
 				Format: shared.FormatJson,
 			},
 		}
-		if err := property.FormatContentValue(n.configuration); err != nil {
+		if err := property.FormatContentValue(utils.CanonicalContent(n.configuration)); err != nil {
 			return nil, fmt.Errorf("generate notificationProp: %w", err)
 		}
 
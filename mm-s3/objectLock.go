@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	"github.com/liuminhaw/mist-miner/shared"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+type objectLockMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *s3Client
+	configuration *s3.GetObjectLockConfigurationOutput
+}
+
+func newObjectLockMiner(serviceClient utils.Client, property string) (*objectLockMiner, error) {
+	client, err := assertS3Client(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newObjectLockMiner: %w", err)
+	}
+
+	return &objectLockMiner{propertyType: property, serviceClient: client}, nil
+}
+
+func (ol *objectLockMiner) PropertyType() string { return ol.propertyType }
+
+func (ol *objectLockMiner) FetchConf(input any) error {
+	objectLockInput, ok := input.(*s3.GetObjectLockConfigurationInput)
+	if !ok {
+		return fmt.Errorf("fetchConf: GetObjectLockConfigurationInput type assertion failed")
+	}
+
+	var err error
+	ol.configuration, err = ol.serviceClient.client.GetObjectLockConfiguration(
+		context.Background(),
+		objectLockInput,
+	)
+	if err != nil {
+		var apiErr smithy.APIError
+		if ok := errors.As(err, &apiErr); ok {
+			switch apiErr.ErrorCode() {
+			case "ObjectLockConfigurationNotFoundError":
+				return &utils.MMError{Category: objectLock, Code: utils.NoConfig}
+			default:
+				return fmt.Errorf("fetchConf bucket objectLock: %w", err)
+			}
+		}
+		return fmt.Errorf("fetchConf bucket objectLock: %w", err)
+	}
+
+	return nil
+}
+
+func (ol *objectLockMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	if err := ol.FetchConf(&s3.GetObjectLockConfigurationInput{Bucket: ol.serviceClient.bucket.Name}); err != nil {
+		return nil, fmt.Errorf("generate bucket objectLock: %w", err)
+	}
+
+	config := ol.configuration.ObjectLockConfiguration
+	if config == nil {
+		return properties, nil
+	}
+
+	type objectLockValue struct {
+		name  string
+		value string
+	}
+
+	values := []objectLockValue{
+		{name: "ObjectLockEnabled", value: string(config.ObjectLockEnabled)},
+	}
+	if config.Rule != nil && config.Rule.DefaultRetention != nil {
+		retention := config.Rule.DefaultRetention
+		period := ""
+		switch {
+		case retention.Years != nil:
+			period = fmt.Sprintf("%d Years", aws.ToInt32(retention.Years))
+		case retention.Days != nil:
+			period = fmt.Sprintf("%d Days", aws.ToInt32(retention.Days))
+		}
+
+		values = append(values,
+			objectLockValue{name: "DefaultRetentionMode", value: string(retention.Mode)},
+			objectLockValue{name: "DefaultRetentionPeriod", value: period},
+		)
+	}
+
+	for _, v := range values {
+		property := shared.MinerProperty{
+			Type: objectLock,
+			Label: shared.MinerPropertyLabel{
+				Name:   v.name,
+				Unique: true,
+			},
+			Content: shared.MinerPropertyContent{
+				Format: shared.FormatText,
+			},
+		}
+		if err := property.FormatContentValue(v.value); err != nil {
+			return nil, fmt.Errorf("generate bucket objectLock: %w", err)
+		}
+		properties = append(properties, property)
+	}
+
+	return properties, nil
+}
@@ -0,0 +1,565 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/liuminhaw/mist-miner/shared"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+// objectStatsConfig is the opt-in object statistics setting from equipment
+type objectStatsConfig struct {
+	enabled bool
+	// maximum number of listed objects, versions and delete markers per bucket
+	maxObjects int
+	// prefixes to walk, the whole bucket when empty
+	prefixes []string
+}
+
+type storageClassStats struct {
+	Objects int64
+	Bytes   int64
+}
+
+type objectStats struct {
+	// ListObjectsV2, ListObjectVersions or Inventory
+	Source            string
+	InventoryId       string
+	InventoryManifest string
+	Prefixes          []string
+	// current objects, delete markers excluded
+	Objects            int64
+	TotalBytes         int64
+	StorageClasses     map[string]*storageClassStats
+	NewestLastModified *time.Time
+	OldestLastModified *time.Time
+	NoncurrentVersions int64
+	DeleteMarkers      int64
+	// Truncated is set when the object cap stopped the listing
+	Truncated bool
+}
+
+func newObjectStats(source string, prefixes []string) *objectStats {
+	return &objectStats{
+		Source:         source,
+		Prefixes:       prefixes,
+		StorageClasses: map[string]*storageClassStats{},
+	}
+}
+
+// addObject counts a current object
+func (s *objectStats) addObject(size int64, storageClass string, lastModified *time.Time) {
+	if storageClass == "" {
+		storageClass = string(types.ObjectStorageClassStandard)
+	}
+	if _, ok := s.StorageClasses[storageClass]; !ok {
+		s.StorageClasses[storageClass] = &storageClassStats{}
+	}
+
+	s.Objects++
+	s.TotalBytes += size
+	s.StorageClasses[storageClass].Objects++
+	s.StorageClasses[storageClass].Bytes += size
+	s.lastModified(lastModified)
+}
+
+func (s *objectStats) lastModified(lastModified *time.Time) {
+	if lastModified == nil {
+		return
+	}
+	if s.NewestLastModified == nil || lastModified.After(*s.NewestLastModified) {
+		s.NewestLastModified = aws.Time(*lastModified)
+	}
+	if s.OldestLastModified == nil || lastModified.Before(*s.OldestLastModified) {
+		s.OldestLastModified = aws.Time(*lastModified)
+	}
+}
+
+// matchPrefix reports whether the key is under one of the configured prefixes
+func (s *objectStats) matchPrefix(key string) bool {
+	if len(s.Prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range s.Prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// listed returns the number of entries counted so far, checked against the object cap
+func (s *objectStats) listed() int {
+	return int(s.Objects + s.NoncurrentVersions + s.DeleteMarkers)
+}
+
+// objectStatsMiner gets the bucket object statistics, from the latest csv inventory report
+// when the bucket has an inventory configuration, otherwise from a capped object listing.
+type objectStatsMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *s3Client
+	stats         *objectStats
+}
+
+func newObjectStatsMiner(serviceClient utils.Client, property string) (*objectStatsMiner, error) {
+	client, err := assertS3Client(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newObjectStatsMiner: %w", err)
+	}
+
+	return &objectStatsMiner{propertyType: property, serviceClient: client}, nil
+}
+
+func (o *objectStatsMiner) PropertyType() string { return o.propertyType }
+
+func (o *objectStatsMiner) FetchConf(input any) error {
+	bucket, ok := input.(*types.Bucket)
+	if !ok {
+		return fmt.Errorf("fetchConf: Bucket type assertion failed")
+	}
+
+	inventory, err := o.inventoryConfiguration(bucket)
+	if err != nil {
+		o.Logger().Info(
+			"inventory not available, list objects instead",
+			utils.ErrorArgs(err)...,
+		)
+	}
+	if inventory != nil {
+		o.stats, err = o.inventoryStats(bucket, inventory)
+		if err == nil {
+			return nil
+		}
+		o.Logger().Info(
+			"inventory not readable, list objects instead",
+			append([]any{"inventory", aws.ToString(inventory.Id)}, utils.ErrorArgs(err)...)...,
+		)
+	}
+
+	o.stats, err = o.listingStats(bucket)
+	if err != nil {
+		return fmt.Errorf("fetchConf: object stats: %w", err)
+	}
+
+	return nil
+}
+
+func (o *objectStatsMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	if err := o.FetchConf(o.serviceClient.bucket); err != nil {
+		return nil, fmt.Errorf("generate bucket objectStats: %w", err)
+	}
+
+	property := shared.MinerProperty{
+		Type: objectStatsProperty,
+		Label: shared.MinerPropertyLabel{
+			Name:   "ObjectStats",
+			Unique: true,
+		},
+		Content: shared.MinerPropertyContent{
+			Format: shared.FormatJson,
+		},
+	}
+	if err := property.FormatContentValue(utils.CanonicalContent(o.stats)); err != nil {
+		return nil, fmt.Errorf("generate bucket objectStats: %w", err)
+	}
+
+	properties = append(properties, property)
+	return properties, nil
+}
+
+// inventoryConfiguration returns the first enabled csv inventory configuration of the bucket,
+// nil when there is none
+func (o *objectStatsMiner) inventoryConfiguration(
+	bucket *types.Bucket,
+) (*types.InventoryConfiguration, error) {
+	input := &s3.ListBucketInventoryConfigurationsInput{Bucket: bucket.Name}
+	for {
+		output, err := o.serviceClient.client.ListBucketInventoryConfigurations(
+			context.Background(),
+			input,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("inventoryConfiguration: %w", err)
+		}
+
+		for i, config := range output.InventoryConfigurationList {
+			if !aws.ToBool(config.IsEnabled) || config.Destination == nil ||
+				config.Destination.S3BucketDestination == nil {
+				continue
+			}
+			if config.Destination.S3BucketDestination.Format != types.InventoryFormatCsv {
+				o.Logger().Debug(
+					"inventory format not supported for object stats",
+					"inventory", aws.ToString(config.Id),
+					"format", config.Destination.S3BucketDestination.Format,
+				)
+				continue
+			}
+			return &output.InventoryConfigurationList[i], nil
+		}
+
+		if !aws.ToBool(output.IsTruncated) {
+			return nil, nil
+		}
+		input.ContinuationToken = output.NextContinuationToken
+	}
+}
+
+type inventoryManifest struct {
+	SourceBucket      string `json:"sourceBucket"`
+	DestinationBucket string `json:"destinationBucket"`
+	FileFormat        string `json:"fileFormat"`
+	FileSchema        string `json:"fileSchema"`
+	Files             []struct {
+		Key string `json:"key"`
+	} `json:"files"`
+}
+
+// inventoryStats reads the latest manifest of the inventory configuration and counts the
+// objects of its report files
+func (o *objectStatsMiner) inventoryStats(
+	bucket *types.Bucket,
+	inventory *types.InventoryConfiguration,
+) (*objectStats, error) {
+	destination := inventory.Destination.S3BucketDestination
+	// Destination bucket is given as arn:<partition>:s3:::<bucket>
+	destinationBucket := aws.ToString(destination.Bucket)
+	destinationBucket = destinationBucket[strings.LastIndex(destinationBucket, ":")+1:]
+	client, err := o.bucketClient(destinationBucket)
+	if err != nil {
+		return nil, fmt.Errorf("inventoryStats: %w", err)
+	}
+
+	manifestKey, err := o.latestManifest(
+		client,
+		destinationBucket,
+		aws.ToString(destination.Prefix),
+		aws.ToString(bucket.Name),
+		aws.ToString(inventory.Id),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("inventoryStats: %w", err)
+	}
+
+	var manifest inventoryManifest
+	if err := o.readObject(client, destinationBucket, manifestKey, func(body io.Reader) error {
+		return json.NewDecoder(body).Decode(&manifest)
+	}); err != nil {
+		return nil, fmt.Errorf("inventoryStats: manifest %s: %w", manifestKey, err)
+	}
+	if manifest.FileFormat != "CSV" {
+		return nil, fmt.Errorf("inventoryStats: manifest %s: unsupported format %s",
+			manifestKey, manifest.FileFormat)
+	}
+
+	columns := map[string]int{}
+	for i, column := range strings.Split(manifest.FileSchema, ",") {
+		columns[strings.TrimSpace(column)] = i
+	}
+	if _, ok := columns["Key"]; !ok {
+		return nil, fmt.Errorf("inventoryStats: manifest %s: no Key in schema", manifestKey)
+	}
+
+	stats := newObjectStats("Inventory", o.serviceClient.objectStats.prefixes)
+	stats.InventoryId = aws.ToString(inventory.Id)
+	stats.InventoryManifest = manifestKey
+	for _, file := range manifest.Files {
+		if err := o.readObject(client, destinationBucket, file.Key, func(body io.Reader) error {
+			return readInventoryFile(body, columns, stats)
+		}); err != nil {
+			return nil, fmt.Errorf("inventoryStats: report %s: %w", file.Key, err)
+		}
+	}
+
+	return stats, nil
+}
+
+// latestManifest finds the manifest.json of the most recent inventory report, reports are
+// delivered under <prefix>/<source bucket>/<inventory id>/<YYYY-MM-DDTHH-MMZ>/
+func (o *objectStatsMiner) latestManifest(
+	client *s3.Client,
+	destinationBucket, prefix, sourceBucket, inventoryId string,
+) (string, error) {
+	reportPrefix := fmt.Sprintf("%s/%s/", sourceBucket, inventoryId)
+	if prefix != "" {
+		reportPrefix = strings.TrimSuffix(prefix, "/") + "/" + reportPrefix
+	}
+
+	latest := ""
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(destinationBucket),
+		Prefix:    aws.String(reportPrefix),
+		Delimiter: aws.String("/"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return "", fmt.Errorf("latestManifest: %w", err)
+		}
+		for _, commonPrefix := range page.CommonPrefixes {
+			date := strings.TrimPrefix(aws.ToString(commonPrefix.Prefix), reportPrefix)
+			// Skip the data/ and hive/ folders
+			if date == "" || date[0] < '0' || date[0] > '9' {
+				continue
+			}
+			if date > latest {
+				latest = date
+			}
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("latestManifest: no report under %s/%s", destinationBucket, reportPrefix)
+	}
+
+	return reportPrefix + latest + "manifest.json", nil
+}
+
+// bucketClient returns the s3 client of the bucket region, the inventory destination bucket
+// may be in another region than the mined bucket
+func (o *objectStatsMiner) bucketClient(bucket string) (*s3.Client, error) {
+	if o.serviceClient.regional == nil || bucket == aws.ToString(o.serviceClient.bucket.Name) {
+		return o.serviceClient.client, nil
+	}
+
+	region, err := getBucketRegion(
+		hclog.WithContext(context.Background(), o.Logger()),
+		o.serviceClient.client,
+		bucket,
+		o.serviceClient.partition,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("bucketClient: %w", err)
+	}
+
+	return o.serviceClient.regional.get(region).s3, nil
+}
+
+func (o *objectStatsMiner) readObject(
+	client *s3.Client,
+	bucket, key string,
+	read func(io.Reader) error,
+) error {
+	output, err := client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("readObject: %w", err)
+	}
+	defer output.Body.Close()
+
+	var body io.Reader = output.Body
+	if strings.HasSuffix(key, ".gz") {
+		reader, err := gzip.NewReader(output.Body)
+		if err != nil {
+			return fmt.Errorf("readObject: %w", err)
+		}
+		defer reader.Close()
+		body = reader
+	}
+
+	return read(body)
+}
+
+// readInventoryFile counts the objects of a csv inventory report file. Versions and delete
+// markers are only reported by inventories including all object versions.
+func readInventoryFile(body io.Reader, columns map[string]int, stats *objectStats) error {
+	column := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("readInventoryFile: %w", err)
+		}
+
+		// Keys are url encoded in csv reports
+		key, err := url.QueryUnescape(column(record, "Key"))
+		if err != nil {
+			key = column(record, "Key")
+		}
+		if !stats.matchPrefix(key) {
+			continue
+		}
+
+		switch {
+		case column(record, "IsDeleteMarker") == "true":
+			stats.DeleteMarkers++
+		case column(record, "IsLatest") == "false":
+			stats.NoncurrentVersions++
+		default:
+			size, _ := strconv.ParseInt(column(record, "Size"), 10, 64)
+			var lastModified *time.Time
+			if modified, err := time.Parse(
+				time.RFC3339,
+				column(record, "LastModifiedDate"),
+			); err == nil {
+				lastModified = &modified
+			}
+			stats.addObject(size, column(record, "StorageClass"), lastModified)
+		}
+	}
+}
+
+// listingStats walks the configured prefixes with ListObjectVersions when the bucket has
+// versioning, ListObjectsV2 otherwise, up to the object cap
+func (o *objectStatsMiner) listingStats(bucket *types.Bucket) (*objectStats, error) {
+	config := o.serviceClient.objectStats
+	prefixes := config.prefixes
+	if len(prefixes) == 0 {
+		prefixes = []string{""}
+	}
+
+	versioningOutput, err := o.serviceClient.client.GetBucketVersioning(
+		context.Background(),
+		&s3.GetBucketVersioningInput{Bucket: bucket.Name},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listingStats: %w", err)
+	}
+
+	if versioningOutput.Status == "" {
+		stats := newObjectStats("ListObjectsV2", config.prefixes)
+		for _, prefix := range prefixes {
+			if err := o.listObjects(bucket, prefix, stats); err != nil {
+				return nil, fmt.Errorf("listingStats: %w", err)
+			}
+		}
+		return stats, nil
+	}
+
+	stats := newObjectStats("ListObjectVersions", config.prefixes)
+	for _, prefix := range prefixes {
+		if err := o.listObjectVersions(bucket, prefix, stats); err != nil {
+			return nil, fmt.Errorf("listingStats: %w", err)
+		}
+	}
+	return stats, nil
+}
+
+func (o *objectStatsMiner) listObjects(
+	bucket *types.Bucket,
+	prefix string,
+	stats *objectStats,
+) error {
+	maxObjects := o.serviceClient.objectStats.maxObjects
+
+	paginator := s3.NewListObjectsV2Paginator(o.serviceClient.client, &s3.ListObjectsV2Input{
+		Bucket: bucket.Name,
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return fmt.Errorf("listObjects: %w", err)
+		}
+		for _, object := range page.Contents {
+			if stats.listed() >= maxObjects {
+				stats.Truncated = true
+				return nil
+			}
+			stats.addObject(
+				aws.ToInt64(object.Size),
+				string(object.StorageClass),
+				object.LastModified,
+			)
+		}
+	}
+
+	return nil
+}
+
+func (o *objectStatsMiner) listObjectVersions(
+	bucket *types.Bucket,
+	prefix string,
+	stats *objectStats,
+) error {
+	maxObjects := o.serviceClient.objectStats.maxObjects
+
+	input := &s3.ListObjectVersionsInput{Bucket: bucket.Name, Prefix: aws.String(prefix)}
+	for {
+		page, err := o.serviceClient.client.ListObjectVersions(context.Background(), input)
+		if err != nil {
+			return fmt.Errorf("listObjectVersions: %w", err)
+		}
+
+		for _, version := range page.Versions {
+			if stats.listed() >= maxObjects {
+				stats.Truncated = true
+				return nil
+			}
+			if !aws.ToBool(version.IsLatest) {
+				stats.NoncurrentVersions++
+				continue
+			}
+			stats.addObject(
+				aws.ToInt64(version.Size),
+				string(version.StorageClass),
+				version.LastModified,
+			)
+		}
+		for range page.DeleteMarkers {
+			if stats.listed() >= maxObjects {
+				stats.Truncated = true
+				return nil
+			}
+			stats.DeleteMarkers++
+		}
+
+		if !aws.ToBool(page.IsTruncated) {
+			return nil
+		}
+		input.KeyMarker = page.NextKeyMarker
+		input.VersionIdMarker = page.NextVersionIdMarker
+	}
+}
+
+// parsePrefixes splits a comma separated prefix list, dropping empty prefixes and the ones
+// already covered by a shorter prefix, so that no object is counted twice
+func parsePrefixes(value string) []string {
+	candidates := []string{}
+	for _, prefix := range strings.Split(value, ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			candidates = append(candidates, prefix)
+		}
+	}
+	sort.Strings(candidates)
+
+	prefixes := []string{}
+	for _, prefix := range candidates {
+		if len(prefixes) > 0 && strings.HasPrefix(prefix, prefixes[len(prefixes)-1]) {
+			continue
+		}
+		prefixes = append(prefixes, prefix)
+	}
+
+	return prefixes
+}
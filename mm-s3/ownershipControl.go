@@ -12,6 +12,8 @@ import (
 )
 
 type ownershipControlMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *s3Client
 	configuration *s3.GetBucketOwnershipControlsOutput
@@ -76,7 +78,7 @@ func (oc *ownershipControlMiner) Generate(dummy utils.CacheInfo) ([]shared.Miner
 				Format: shared.FormatJson,
 			},
 		}
-		if err := property.FormatContentValue(oc.configuration.OwnershipControls); err != nil {
+		if err := property.FormatContentValue(utils.CanonicalContent(oc.configuration.OwnershipControls)); err != nil {
 			return nil, fmt.Errorf("generate bucket ownershipControlProp: %w", err)
 		}
 
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+// bucketActions are the actions listing the buckets and finding their region, HeadBucket
+// being the fallback of GetBucketLocation
+var bucketActions = []string{"s3:ListAllMyBuckets", "s3:GetBucketLocation", "s3:ListBucket"}
+
+// multiRegionAccessPointActions are the actions listing the multi-region access points
+var multiRegionAccessPointActions = []string{"s3:ListMultiRegionAccessPoints"}
+
+// kmsKeyActions are the actions resolving the kms key of the bucket encryption
+var kmsKeyActions = []string{
+	"kms:DescribeKey",
+	"kms:GetKeyPolicy",
+	"kms:GetKeyRotationStatus",
+	"kms:ListAliases",
+}
+
+// objectStatsActions are the actions of the opt-in object statistics, inventory reports
+// are read from their destination bucket
+var objectStatsActions = []string{
+	"s3:GetBucketVersioning",
+	"s3:GetInventoryConfiguration",
+	"s3:GetObject",
+	"s3:ListBucket",
+	"s3:ListBucketVersions",
+}
+
+// propertyActions are the actions called by the property crawlers, by property type. The
+// iam action names of s3 differ from the api names in places, e.g. GetBucketEncryption is
+// allowed by s3:GetEncryptionConfiguration.
+var propertyActions = map[string][]string{
+	location:         {},
+	accelerateConfig: {"s3:GetAccelerateConfiguration"},
+	accessPoint: {
+		"s3:ListAccessPoints",
+		"s3:GetAccessPoint",
+		"s3:GetAccessPointPolicy",
+		"s3:GetAccessPointPolicyStatus",
+	},
+	analyticsConfig:     {"s3:GetAnalyticsConfiguration"},
+	acl:                 {"s3:GetBucketAcl"},
+	cors:                {"s3:GetBucketCORS"},
+	encryption:          append([]string{"s3:GetEncryptionConfiguration"}, kmsKeyActions...),
+	intelligentTiering:  {"s3:GetIntelligentTieringConfiguration"},
+	inventory:           {"s3:GetInventoryConfiguration"},
+	lifecycle:           {"s3:GetLifecycleConfiguration"},
+	logging:             {"s3:GetBucketLogging"},
+	metrics:             {"s3:GetMetricsConfiguration"},
+	notification:        {"s3:GetBucketNotification"},
+	objectLock:          {"s3:GetBucketObjectLockConfiguration"},
+	objectStatsProperty: objectStatsActions,
+	ownershipControl:    {"s3:GetBucketOwnershipControls"},
+	policy:              {"s3:GetBucketPolicy"},
+	policyStatus:        {"s3:GetBucketPolicyStatus"},
+	publicAccessBlock:   {"s3:GetBucketPublicAccessBlock"},
+	replication:         {"s3:GetReplicationConfiguration"},
+	requestPayment:      {"s3:GetBucketRequestPayment"},
+	tagging:             {"s3:GetBucketTagging"},
+	versioning:          {"s3:GetBucketVersioning"},
+	website:             {"s3:GetBucketWebsite"},
+
+	accountPublicAccessBlock: {"s3:GetAccountPublicAccessBlock"},
+
+	multiRegionAccessPointDetail:            {},
+	multiRegionAccessPointPublicAccessBlock: {"s3:GetMultiRegionAccessPoint"},
+	multiRegionAccessPointPolicy:            {"s3:GetMultiRegionAccessPointPolicy"},
+	multiRegionAccessPointPolicyStatus:      {"s3:GetMultiRegionAccessPointPolicyStatus"},
+}
+
+// requiredPermissions returns the actions needed to mine the buckets with the property
+// crawlers of the run, the account and the multi-region access points in the aws partition
+func requiredPermissions(
+	constructors []utils.PropsCrawlerConstructor,
+	partition utils.Partition,
+) (utils.Permissions, error) {
+	permissions := utils.Permissions{}
+	permissions.Add(bucketActions...)
+	if err := permissions.AddCrawlers(&s3Client{}, constructors, propertyActions); err != nil {
+		return nil, fmt.Errorf("requiredPermissions: %w", err)
+	}
+	if err := permissions.AddCrawlers(
+		&s3ControlClient{},
+		accountPropsConstructors,
+		propertyActions,
+	); err != nil {
+		return nil, fmt.Errorf("requiredPermissions: %w", err)
+	}
+
+	if partition == utils.PartitionAws {
+		permissions.Add(multiRegionAccessPointActions...)
+		if err := permissions.AddCrawlers(
+			&s3ControlClient{},
+			multiRegionAccessPointPropsConstructors,
+			propertyActions,
+		); err != nil {
+			return nil, fmt.Errorf("requiredPermissions: %w", err)
+		}
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+func TestRequiredPermissions(t *testing.T) {
+	tests := []struct {
+		name        string
+		statsConfig objectStatsConfig
+		compatible  bool
+		partition   utils.Partition
+		want        []string
+		notWant     []string
+	}{
+		{
+			name:      "object statistics disabled",
+			partition: utils.PartitionAws,
+			want:      []string{"s3:GetEncryptionConfiguration", "s3:ListMultiRegionAccessPoints"},
+			notWant:   []string{"s3:GetObject", "s3:ListBucketVersions"},
+		},
+		{
+			name:        "object statistics enabled",
+			statsConfig: objectStatsConfig{enabled: true},
+			partition:   utils.PartitionAws,
+			want:        []string{"s3:GetObject", "s3:ListBucketVersions"},
+		},
+		{
+			name:       "s3-compatible crawlers",
+			compatible: true,
+			partition:  utils.PartitionAws,
+			want:       []string{"s3:GetBucketCORS"},
+			notWant:    []string{"s3:GetObject"},
+		},
+		{
+			name:      "china partition",
+			partition: utils.PartitionAwsCn,
+			want:      []string{"s3:GetBucketPolicy"},
+			notWant:   []string{"s3:ListMultiRegionAccessPoints"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			permissions, err := requiredPermissions(
+				bucketPropsConstructors(tt.statsConfig, tt.compatible),
+				tt.partition,
+			)
+			if err != nil {
+				t.Fatalf("requiredPermissions() error = %v", err)
+			}
+			actions := permissions.Actions()
+			for _, action := range tt.want {
+				if !slices.Contains(actions, action) {
+					t.Errorf("requiredPermissions() misses %s", action)
+				}
+			}
+			for _, action := range tt.notWant {
+				if slices.Contains(actions, action) {
+					t.Errorf("requiredPermissions() has %s", action)
+				}
+			}
+		})
+	}
+}
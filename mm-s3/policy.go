@@ -12,6 +12,8 @@ import (
 )
 
 type policyMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *s3Client
 	configuration *s3.GetBucketPolicyOutput
@@ -12,6 +12,8 @@ import (
 )
 
 type policyStatusMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *s3Client
 	configuration *s3.GetBucketPolicyStatusOutput
@@ -73,7 +75,7 @@ func (ps *policyStatusMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerProp
 				Format: shared.FormatJson,
 			},
 		}
-		if err := property.FormatContentValue(ps.configuration.PolicyStatus); err != nil {
+		if err := property.FormatContentValue(utils.CanonicalContent(ps.configuration.PolicyStatus)); err != nil {
 			return nil, fmt.Errorf("generate bucket policyStatus: %w", err)
 		}
 
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3control"
+	"github.com/aws/smithy-go"
+	"github.com/liuminhaw/mist-miner/shared"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+// publicAccessBlockFlags generates one property for each of the four block public access
+// settings, shared by the bucket, access point and account level configurations. Labels are
+// the setting names prefixed with labelPrefix.
+func publicAccessBlockFlags(
+	propertyType string,
+	labelPrefix string,
+	blockPublicAcls, ignorePublicAcls, blockPublicPolicy, restrictPublicBuckets *bool,
+) ([]shared.MinerProperty, error) {
+	properties := []shared.MinerProperty{}
+
+	flags := []struct {
+		name  string
+		value *bool
+	}{
+		{name: "BlockPublicAcls", value: blockPublicAcls},
+		{name: "IgnorePublicAcls", value: ignorePublicAcls},
+		{name: "BlockPublicPolicy", value: blockPublicPolicy},
+		{name: "RestrictPublicBuckets", value: restrictPublicBuckets},
+	}
+	for _, flag := range flags {
+		property := shared.MinerProperty{
+			Type: propertyType,
+			Label: shared.MinerPropertyLabel{
+				Name:   labelPrefix + flag.name,
+				Unique: true,
+			},
+			Content: shared.MinerPropertyContent{
+				Format: shared.FormatText,
+			},
+		}
+		if err := property.FormatContentValue(strconv.FormatBool(aws.ToBool(flag.value))); err != nil {
+			return nil, fmt.Errorf("publicAccessBlockFlags: %w", err)
+		}
+		properties = append(properties, property)
+	}
+
+	return properties, nil
+}
+
+type publicAccessBlockMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *s3Client
+	configuration *s3.GetPublicAccessBlockOutput
+}
+
+func newPublicAccessBlockMiner(
+	serviceClient utils.Client,
+	property string,
+) (*publicAccessBlockMiner, error) {
+	client, err := assertS3Client(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newPublicAccessBlockMiner: %w", err)
+	}
+
+	return &publicAccessBlockMiner{propertyType: property, serviceClient: client}, nil
+}
+
+func (pab *publicAccessBlockMiner) PropertyType() string { return pab.propertyType }
+
+func (pab *publicAccessBlockMiner) FetchConf(input any) error {
+	publicAccessBlockInput, ok := input.(*s3.GetPublicAccessBlockInput)
+	if !ok {
+		return fmt.Errorf("fetchConf: GetPublicAccessBlockInput type assertion failed")
+	}
+
+	var err error
+	pab.configuration, err = pab.serviceClient.client.GetPublicAccessBlock(
+		context.Background(),
+		publicAccessBlockInput,
+	)
+	if err != nil {
+		var apiErr smithy.APIError
+		if ok := errors.As(err, &apiErr); ok {
+			switch apiErr.ErrorCode() {
+			case "NoSuchPublicAccessBlockConfiguration":
+				return &utils.MMError{Category: publicAccessBlock, Code: utils.NoConfig}
+			default:
+				return fmt.Errorf("fetchConf bucket publicAccessBlock: %w", err)
+			}
+		}
+		return fmt.Errorf("fetchConf bucket publicAccessBlock: %w", err)
+	}
+
+	return nil
+}
+
+func (pab *publicAccessBlockMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerProperty, error) {
+	if err := pab.FetchConf(&s3.GetPublicAccessBlockInput{Bucket: pab.serviceClient.bucket.Name}); err != nil {
+		return nil, fmt.Errorf("generate bucket publicAccessBlock: %w", err)
+	}
+
+	config := pab.configuration.PublicAccessBlockConfiguration
+	if config == nil {
+		return []shared.MinerProperty{}, nil
+	}
+
+	properties, err := publicAccessBlockFlags(
+		publicAccessBlock,
+		"",
+		config.BlockPublicAcls,
+		config.IgnorePublicAcls,
+		config.BlockPublicPolicy,
+		config.RestrictPublicBuckets,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("generate bucket publicAccessBlock: %w", err)
+	}
+
+	return properties, nil
+}
+
+// account level block public access (s3control GetPublicAccessBlock)
+type accountPublicAccessBlockMiner struct {
+	utils.CrawlerLogger
+
+	propertyType  string
+	serviceClient *s3ControlClient
+	configuration *s3control.GetPublicAccessBlockOutput
+}
+
+func newAccountPublicAccessBlockMiner(
+	serviceClient utils.Client,
+	property string,
+) (*accountPublicAccessBlockMiner, error) {
+	client, err := assertS3ControlClient(serviceClient)
+	if err != nil {
+		return nil, fmt.Errorf("newAccountPublicAccessBlockMiner: %w", err)
+	}
+
+	return &accountPublicAccessBlockMiner{propertyType: property, serviceClient: client}, nil
+}
+
+func (apab *accountPublicAccessBlockMiner) PropertyType() string { return apab.propertyType }
+
+func (apab *accountPublicAccessBlockMiner) FetchConf(input any) error {
+	publicAccessBlockInput, ok := input.(*s3control.GetPublicAccessBlockInput)
+	if !ok {
+		return fmt.Errorf("fetchConf: GetPublicAccessBlockInput type assertion failed")
+	}
+
+	var err error
+	apab.configuration, err = apab.serviceClient.client.GetPublicAccessBlock(
+		context.Background(),
+		publicAccessBlockInput,
+	)
+	if err != nil {
+		var apiErr smithy.APIError
+		if ok := errors.As(err, &apiErr); ok {
+			switch apiErr.ErrorCode() {
+			case "NoSuchPublicAccessBlockConfiguration":
+				return &utils.MMError{Category: accountPublicAccessBlock, Code: utils.NoConfig}
+			default:
+				return fmt.Errorf("fetchConf account publicAccessBlock: %w", err)
+			}
+		}
+		return fmt.Errorf("fetchConf account publicAccessBlock: %w", err)
+	}
+
+	return nil
+}
+
+func (apab *accountPublicAccessBlockMiner) Generate(
+	dummy utils.CacheInfo,
+) ([]shared.MinerProperty, error) {
+	if err := apab.FetchConf(&s3control.GetPublicAccessBlockInput{
+		AccountId: aws.String(apab.serviceClient.accountId),
+	}); err != nil {
+		return nil, fmt.Errorf("generate account publicAccessBlock: %w", err)
+	}
+
+	config := apab.configuration.PublicAccessBlockConfiguration
+	if config == nil {
+		return []shared.MinerProperty{}, nil
+	}
+
+	properties, err := publicAccessBlockFlags(
+		accountPublicAccessBlock,
+		"",
+		config.BlockPublicAcls,
+		config.IgnorePublicAcls,
+		config.BlockPublicPolicy,
+		config.RestrictPublicBuckets,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("generate account publicAccessBlock: %w", err)
+	}
+
+	return properties, nil
+}
@@ -6,6 +6,8 @@ import (
 )
 
 type regionMiner struct {
+	utils.CrawlerLogger
+
 	propertyType string
 }
 
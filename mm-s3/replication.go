@@ -4,14 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/smithy-go"
 	"github.com/liuminhaw/mist-miner/shared"
 	"github.com/liuminhaw/mm-plugins/utils"
 )
 
 type replicationMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *s3Client
 	configuration *s3.GetBucketReplicationOutput
@@ -62,23 +67,132 @@ func (r *replicationMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerProper
 		return nil, fmt.Errorf("generate bucket replication: %w", err)
 	}
 
-	if r.configuration.ReplicationConfiguration != nil {
+	config := r.configuration.ReplicationConfiguration
+	if config == nil {
+		return properties, nil
+	}
+
+	property := shared.MinerProperty{
+		Type: replicationRole,
+		Label: shared.MinerPropertyLabel{
+			Name:   "Role",
+			Unique: true,
+		},
+		Content: shared.MinerPropertyContent{
+			Format: shared.FormatText,
+		},
+	}
+	if err := property.FormatContentValue(aws.ToString(config.Role)); err != nil {
+		return nil, fmt.Errorf("generate bucket replication: %w", err)
+	}
+	properties = append(properties, property)
+
+	labels := map[string]int{}
+	for _, rule := range config.Rules {
+		// Rules sharing the same label are numbered in their configuration order
+		label := replicationRuleLabel(rule)
+		labels[label]++
+		if labels[label] > 1 {
+			label = fmt.Sprintf("%s%s%d", label, valueSeparator, labels[label])
+		}
+
 		property := shared.MinerProperty{
 			Type: replication,
 			Label: shared.MinerPropertyLabel{
-				Name:   "Replication",
+				Name:   label,
 				Unique: true,
 			},
 			Content: shared.MinerPropertyContent{
 				Format: shared.FormatJson,
 			},
 		}
-		if err := property.FormatContentValue(r.configuration.ReplicationConfiguration); err != nil {
-			return nil, fmt.Errorf("generate buckeP replication: %w", err)
+		if err := property.FormatContentValue(utils.CanonicalContent(newReplicationRuleContent(rule))); err != nil {
+			return nil, fmt.Errorf("generate bucket replication: %w", err)
 		}
-
 		properties = append(properties, property)
 	}
 
 	return properties, nil
 }
+
+// replicationRuleLabel labels a rule by its id when set, otherwise by its priority and
+// destination bucket, e.g. 1|arn:aws:s3:::destination. Rules of the earlier schema have no
+// priority and are told apart by their prefix instead.
+func replicationRuleLabel(rule types.ReplicationRule) string {
+	if id := aws.ToString(rule.ID); id != "" {
+		return id
+	}
+
+	order := aws.ToString(rule.Prefix)
+	if rule.Priority != nil {
+		order = strconv.Itoa(int(aws.ToInt32(rule.Priority)))
+	}
+	destination := ""
+	if rule.Destination != nil {
+		destination = aws.ToString(rule.Destination.Bucket)
+	}
+
+	return order + valueSeparator + destination
+}
+
+// replicationRuleContent is a replication rule with its destination settings as fields
+type replicationRuleContent struct {
+	ID                        string
+	Status                    types.ReplicationRuleStatus
+	Priority                  *int32
+	Prefix                    string
+	Filter                    types.ReplicationRuleFilter
+	DestinationBucket         string
+	DestinationAccount        string
+	StorageClass              types.StorageClass
+	ReplicaKmsKeyID           string
+	OwnershipOverride         types.OwnerOverride
+	ReplicationTime           types.ReplicationTimeStatus
+	ReplicationTimeMinutes    *int32
+	Metrics                   types.MetricsStatus
+	DeleteMarkerReplication   types.DeleteMarkerReplicationStatus
+	ExistingObjectReplication types.ExistingObjectReplicationStatus
+	SourceSelectionCriteria   *types.SourceSelectionCriteria
+}
+
+func newReplicationRuleContent(rule types.ReplicationRule) replicationRuleContent {
+	content := replicationRuleContent{
+		ID:                      aws.ToString(rule.ID),
+		Status:                  rule.Status,
+		Priority:                rule.Priority,
+		Prefix:                  aws.ToString(rule.Prefix),
+		Filter:                  rule.Filter,
+		SourceSelectionCriteria: rule.SourceSelectionCriteria,
+	}
+	if rule.DeleteMarkerReplication != nil {
+		content.DeleteMarkerReplication = rule.DeleteMarkerReplication.Status
+	}
+	if rule.ExistingObjectReplication != nil {
+		content.ExistingObjectReplication = rule.ExistingObjectReplication.Status
+	}
+
+	destination := rule.Destination
+	if destination == nil {
+		return content
+	}
+	content.DestinationBucket = aws.ToString(destination.Bucket)
+	content.DestinationAccount = aws.ToString(destination.Account)
+	content.StorageClass = destination.StorageClass
+	if destination.EncryptionConfiguration != nil {
+		content.ReplicaKmsKeyID = aws.ToString(destination.EncryptionConfiguration.ReplicaKmsKeyID)
+	}
+	if destination.AccessControlTranslation != nil {
+		content.OwnershipOverride = destination.AccessControlTranslation.Owner
+	}
+	if destination.ReplicationTime != nil {
+		content.ReplicationTime = destination.ReplicationTime.Status
+		if destination.ReplicationTime.Time != nil {
+			content.ReplicationTimeMinutes = destination.ReplicationTime.Time.Minutes
+		}
+	}
+	if destination.Metrics != nil {
+		content.Metrics = destination.Metrics.Status
+	}
+
+	return content
+}
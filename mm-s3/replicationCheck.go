@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/liuminhaw/mist-miner/shared"
+	"github.com/liuminhaw/mm-plugins/utils"
+)
+
+// replicationDestination holds the fields of a mined replication rule used by the checks
+type replicationDestination struct {
+	Status             types.ReplicationRuleStatus
+	DestinationBucket  string
+	DestinationAccount string
+	OwnershipOverride  types.OwnerOverride
+}
+
+type replicationCheck struct {
+	DestinationBucket          string
+	DestinationVersioning      string
+	DestinationObjectOwnership string
+	// PASS or FAIL
+	Status string
+	Issues []string
+}
+
+// replicationChecks cross-checks the enabled replication rules of the mined buckets against
+// their destination bucket, when it is mined in this run: the destination must have versioning
+// enabled, a destination disabling acls needs the ownership override, and the destination
+// account of the rule must be the account owning the destination bucket.
+func replicationChecks(resources shared.MinerResources, accountId string) error {
+	buckets := map[string]int{}
+	for i, resource := range resources {
+		// Buckets whose checked settings could not be read are not checked against
+		if hasMiningError(resource, location, versioning, ownershipControl) {
+			continue
+		}
+		buckets[resource.Identifier] = i
+	}
+
+	for i := range resources {
+		if hasMiningError(resources[i], ownershipControl) {
+			continue
+		}
+		checks := []shared.MinerProperty{}
+		for _, property := range resources[i].Properties {
+			if property.Type != replication {
+				continue
+			}
+
+			var rule replicationDestination
+			if err := json.Unmarshal([]byte(property.Content.Value), &rule); err != nil {
+				return fmt.Errorf("replicationChecks: %w", err)
+			}
+			if rule.Status != types.ReplicationRuleStatusEnabled {
+				continue
+			}
+			// Destination bucket is given as arn:<partition>:s3:::<bucket>
+			bucket := rule.DestinationBucket[strings.LastIndex(rule.DestinationBucket, ":")+1:]
+			destination, ok := buckets[bucket]
+			if !ok {
+				continue
+			}
+
+			check, err := newReplicationCheck(
+				rule,
+				bucket,
+				resources[i],
+				resources[destination],
+				accountId,
+			)
+			if err != nil {
+				return fmt.Errorf("replicationChecks: %w", err)
+			}
+			checkProperty := shared.MinerProperty{
+				Type: replicationCheckType,
+				Label: shared.MinerPropertyLabel{
+					Name:   property.Label.Name,
+					Unique: true,
+				},
+				Content: shared.MinerPropertyContent{
+					Format: shared.FormatJson,
+				},
+			}
+			if err := checkProperty.FormatContentValue(utils.CanonicalContent(check)); err != nil {
+				return fmt.Errorf("replicationChecks: %w", err)
+			}
+			checks = append(checks, checkProperty)
+		}
+
+		if len(checks) > 0 {
+			resources[i].Properties = append(resources[i].Properties, checks...)
+			resources[i].Sort()
+		}
+	}
+
+	return nil
+}
+
+func newReplicationCheck(
+	rule replicationDestination,
+	bucket string,
+	source, destination shared.MinerResource,
+	accountId string,
+) (replicationCheck, error) {
+	check := replicationCheck{DestinationBucket: bucket, Issues: []string{}}
+
+	sourceOwnership, err := objectOwnership(source)
+	if err != nil {
+		return check, fmt.Errorf("newReplicationCheck: %w", err)
+	}
+	check.DestinationObjectOwnership, err = objectOwnership(destination)
+	if err != nil {
+		return check, fmt.Errorf("newReplicationCheck: %w", err)
+	}
+	for _, property := range destination.Properties {
+		if property.Type != versioning {
+			continue
+		}
+		var status types.VersioningConfiguration
+		if err := json.Unmarshal([]byte(property.Content.Value), &status); err != nil {
+			return check, fmt.Errorf("newReplicationCheck: %w", err)
+		}
+		check.DestinationVersioning = string(status.Status)
+	}
+
+	if check.DestinationVersioning != string(types.BucketVersioningStatusEnabled) {
+		check.Issues = append(check.Issues, "destination bucket versioning is not enabled")
+	}
+	// With acls disabled on the destination, replicas keeping acls of other grantees than the
+	// bucket owner are rejected unless their ownership is changed to the destination
+	enforced := string(types.ObjectOwnershipBucketOwnerEnforced)
+	if check.DestinationObjectOwnership == enforced && sourceOwnership != enforced &&
+		rule.OwnershipOverride != types.OwnerOverrideDestination {
+		check.Issues = append(check.Issues, fmt.Sprintf(
+			"destination object ownership is %s without ownership override to Destination, "+
+				"objects with acls fail to replicate",
+			enforced,
+		))
+	}
+	if rule.DestinationAccount != "" && accountId != "" && rule.DestinationAccount != accountId {
+		check.Issues = append(check.Issues, fmt.Sprintf(
+			"destination account %s is not the account %s owning the destination bucket",
+			rule.DestinationAccount,
+			accountId,
+		))
+	}
+
+	check.Status = "PASS"
+	if len(check.Issues) > 0 {
+		check.Status = "FAIL"
+	}
+
+	return check, nil
+}
+
+// objectOwnership returns the object ownership of the mined bucket, empty when the bucket has
+// no ownership controls
+func objectOwnership(resource shared.MinerResource) (string, error) {
+	ownership := ""
+	for _, property := range resource.Properties {
+		if property.Type != ownershipControl {
+			continue
+		}
+		var controls types.OwnershipControls
+		if err := json.Unmarshal([]byte(property.Content.Value), &controls); err != nil {
+			return "", fmt.Errorf("objectOwnership: %w", err)
+		}
+		for _, rule := range controls.Rules {
+			ownership = string(rule.ObjectOwnership)
+		}
+	}
+
+	return ownership, nil
+}
+
+// hasMiningError reports whether reading one of the property types of the resource failed
+func hasMiningError(resource shared.MinerResource, propertyTypes ...string) bool {
+	for _, property := range resource.Properties {
+		if property.Type == utils.MiningError && slices.Contains(propertyTypes, property.Label.Name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/liuminhaw/mist-miner/shared"
+)
+
+func TestNewReplicationCheck(t *testing.T) {
+	versioned := testProperty(versioning, "Versioning", `{"Status": "Enabled"}`)
+	ownership := func(value types.ObjectOwnership) shared.MinerProperty {
+		return testProperty(
+			ownershipControl,
+			"OwnershipControls",
+			`{"Rules": [{"ObjectOwnership": "`+string(value)+`"}]}`,
+		)
+	}
+
+	tests := []struct {
+		name        string
+		rule        replicationDestination
+		source      []shared.MinerProperty
+		destination []shared.MinerProperty
+		wantStatus  string
+		wantIssues  int
+	}{
+		{
+			name:        "versioned destination",
+			destination: []shared.MinerProperty{versioned},
+			wantStatus:  "PASS",
+		},
+		{
+			name:       "destination not versioned",
+			wantStatus: "FAIL",
+			wantIssues: 1,
+		},
+		{
+			name: "enforced destination without override",
+			destination: []shared.MinerProperty{
+				versioned,
+				ownership(types.ObjectOwnershipBucketOwnerEnforced),
+			},
+			wantStatus: "FAIL",
+			wantIssues: 1,
+		},
+		{
+			name: "enforced destination with override",
+			rule: replicationDestination{
+				DestinationAccount: testAccountId,
+				OwnershipOverride:  types.OwnerOverrideDestination,
+			},
+			destination: []shared.MinerProperty{
+				versioned,
+				ownership(types.ObjectOwnershipBucketOwnerEnforced),
+			},
+			wantStatus: "PASS",
+		},
+		{
+			name:   "enforced source and destination",
+			source: []shared.MinerProperty{ownership(types.ObjectOwnershipBucketOwnerEnforced)},
+			destination: []shared.MinerProperty{
+				versioned,
+				ownership(types.ObjectOwnershipBucketOwnerEnforced),
+			},
+			wantStatus: "PASS",
+		},
+		{
+			name: "other destination account",
+			rule: replicationDestination{
+				DestinationAccount: "444455556666",
+				OwnershipOverride:  types.OwnerOverrideDestination,
+			},
+			destination: []shared.MinerProperty{versioned},
+			wantStatus:  "FAIL",
+			wantIssues:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check, err := newReplicationCheck(
+				tt.rule,
+				"destination",
+				shared.MinerResource{Identifier: "source", Properties: tt.source},
+				shared.MinerResource{Identifier: "destination", Properties: tt.destination},
+				testAccountId,
+			)
+			if err != nil {
+				t.Fatalf("newReplicationCheck() error = %v", err)
+			}
+			if check.Status != tt.wantStatus || len(check.Issues) != tt.wantIssues {
+				t.Errorf(
+					"newReplicationCheck() = %s %v, want %s with %d issues",
+					check.Status, check.Issues, tt.wantStatus, tt.wantIssues,
+				)
+			}
+		})
+	}
+}
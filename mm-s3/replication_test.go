@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestReplicationRuleLabel(t *testing.T) {
+	destination := &types.Destination{Bucket: aws.String("arn:aws:s3:::destination")}
+	tests := []struct {
+		name string
+		rule types.ReplicationRule
+		want string
+	}{
+		{
+			name: "id",
+			rule: types.ReplicationRule{ID: aws.String("backup"), Priority: aws.Int32(1), Destination: destination},
+			want: "backup",
+		},
+		{
+			name: "priority and destination",
+			rule: types.ReplicationRule{Priority: aws.Int32(2), Destination: destination},
+			want: "2|arn:aws:s3:::destination",
+		},
+		{
+			name: "empty id",
+			rule: types.ReplicationRule{ID: aws.String(""), Priority: aws.Int32(0), Destination: destination},
+			want: "0|arn:aws:s3:::destination",
+		},
+		{
+			name: "prefix without priority",
+			rule: types.ReplicationRule{Prefix: aws.String("logs/"), Destination: destination},
+			want: "logs/|arn:aws:s3:::destination",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := replicationRuleLabel(tt.rule); got != tt.want {
+				t.Errorf("replicationRuleLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,6 +10,8 @@ import (
 )
 
 type requestPaymentMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *s3Client
 	configuration *s3.GetBucketRequestPaymentOutput
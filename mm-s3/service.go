@@ -3,23 +3,61 @@ package main
 import (
 	"errors"
 
+	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3control"
 	"github.com/liuminhaw/mm-plugins/utils"
 )
 
 type s3Client struct {
 	client *s3.Client
 	bucket *types.Bucket
+	// s3control client in the bucket region and the account id, for access points
+	control   *s3control.Client
+	accountId string
+	// kms client in the bucket region, for encryption key resolution
+	kms *kms.Client
+	// opt-in object statistics setting
+	objectStats objectStatsConfig
+	// run summary recording the property crawling time
+	run *utils.MiningRun
+	// clients of the other regions and the account partition, for buckets read besides the
+	// mined one, nil with s3-compatible storage
+	regional  *regionalClients
+	partition utils.Partition
 }
 
-func newS3Client(client *s3.Client, bucket *types.Bucket) *s3Client {
-	return &s3Client{client: client, bucket: bucket}
+func newS3Client(
+	client *s3.Client,
+	bucket *types.Bucket,
+	control *s3control.Client,
+	accountId string,
+	kms *kms.Client,
+	objectStats objectStatsConfig,
+	run *utils.MiningRun,
+	regional *regionalClients,
+	partition utils.Partition,
+) *s3Client {
+	return &s3Client{
+		client:      client,
+		bucket:      bucket,
+		control:     control,
+		accountId:   accountId,
+		kms:         kms,
+		objectStats: objectStats,
+		run:         run,
+		regional:    regional,
+		partition:   partition,
+	}
 }
 
 // Implement the utils.Client interface
 func (s3c *s3Client) Service() string { return "s3" }
 
+// Implement the utils.RunRecorder interface
+func (s3c *s3Client) MiningRun() *utils.MiningRun { return s3c.run }
+
 func assertS3Client(serviceClient utils.Client) (*s3Client, error) {
 	client, ok := serviceClient.(*s3Client)
 	if !ok {
@@ -28,3 +66,32 @@ func assertS3Client(serviceClient utils.Client) (*s3Client, error) {
 
 	return client, nil
 }
+
+type s3ControlClient struct {
+	client    *s3control.Client
+	accountId string
+	run       *utils.MiningRun
+}
+
+func newS3ControlClient(
+	client *s3control.Client,
+	accountId string,
+	run *utils.MiningRun,
+) *s3ControlClient {
+	return &s3ControlClient{client: client, accountId: accountId, run: run}
+}
+
+// Implement the utils.Client interface
+func (s3cc *s3ControlClient) Service() string { return "s3control" }
+
+// Implement the utils.RunRecorder interface
+func (s3cc *s3ControlClient) MiningRun() *utils.MiningRun { return s3cc.run }
+
+func assertS3ControlClient(serviceClient utils.Client) (*s3ControlClient, error) {
+	client, ok := serviceClient.(*s3ControlClient)
+	if !ok {
+		return nil, errors.New("custom s3ControlClient type assertion failed")
+	}
+
+	return client, nil
+}
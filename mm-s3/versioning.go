@@ -10,6 +10,8 @@ import (
 )
 
 type versioningMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *s3Client
 	configuration *s3.GetBucketVersioningOutput
@@ -61,7 +63,7 @@ func (v *versioningMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerPropert
 			Format: shared.FormatJson,
 		},
 	}
-	if err := property.FormatContentValue(v.configuration); err != nil {
+	if err := property.FormatContentValue(utils.CanonicalContent(v.configuration)); err != nil {
 		return nil, fmt.Errorf("generate bucket versioning: %w", err)
 	}
 
@@ -12,6 +12,8 @@ import (
 )
 
 type websiteMiner struct {
+	utils.CrawlerLogger
+
 	propertyType  string
 	serviceClient *s3Client
 	configuration *s3.GetBucketWebsiteOutput
@@ -72,7 +74,7 @@ func (w *websiteMiner) Generate(dummy utils.CacheInfo) ([]shared.MinerProperty,
 			Format: shared.FormatJson,
 		},
 	}
-	if err := property.FormatContentValue(w.configuration); err != nil {
+	if err := property.FormatContentValue(utils.CanonicalContent(w.configuration)); err != nil {
 		return nil, fmt.Errorf("generate bucket website: %w", err)
 	}
 
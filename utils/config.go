@@ -35,6 +35,7 @@ type EquipmentInfo struct {
 // GetEquipAttribute read from given equipments and return the attribute value
 // that matches the given EquipmentInfo.
 // If the attribute is not found, return the default value in equipment info.
+// An empty AcceptVals accepts any non-empty attribute value.
 func GetEquipAttribute(
 	equipments []shared.MinerConfigEquipment,
 	info EquipmentInfo,
@@ -47,6 +48,9 @@ func GetEquipAttribute(
 		}
 	}
 
+	if len(info.AcceptVals) == 0 && result != "" {
+		return result
+	}
 	for _, v := range info.AcceptVals {
 		if result == v {
 			return result
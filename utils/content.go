@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// unionMemberPattern matches sdk union member types, e.g. LifecycleRuleFilterMemberPrefix
+var unionMemberPattern = regexp.MustCompile(`^[A-Za-z0-9]+Member([A-Za-z0-9]+)$`)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// CanonicalContent converts an sdk output or type value into its canonical form for
+// property contents, so that the stored json only depends on the semantic values:
+//   - ResultMetadata and unexported fields are dropped
+//   - nil pointers, empty strings, empty slices and empty maps are omitted
+//   - union members are keyed by their member name, e.g. {"Prefix": "logs/"}
+//   - timestamps are formatted as RFC3339 in UTC
+//
+// Keys are the struct field names, or the json tag names when set.
+func CanonicalContent(v any) any {
+	return canonicalValue(reflect.ValueOf(v))
+}
+
+func canonicalValue(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return canonicalValue(v.Elem())
+	case reflect.Struct:
+		return canonicalStruct(v)
+	case reflect.Slice, reflect.Array:
+		items := []any{}
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, canonicalValue(v.Index(i)))
+		}
+		return items
+	case reflect.Map:
+		content := map[string]any{}
+		iter := v.MapRange()
+		for iter.Next() {
+			content[fmt.Sprint(iter.Key().Interface())] = canonicalValue(iter.Value())
+		}
+		return content
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+
+	return nil
+}
+
+func canonicalStruct(v reflect.Value) any {
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).UTC().Format(time.RFC3339)
+	}
+
+	if match := unionMemberPattern.FindStringSubmatch(v.Type().Name()); match != nil {
+		if member := v.FieldByName("Value"); member.IsValid() {
+			return map[string]any{match[1]: canonicalValue(member)}
+		}
+	}
+
+	content := map[string]any{}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() || field.Name == "ResultMetadata" {
+			continue
+		}
+
+		name := field.Name
+		if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+
+		value := canonicalValue(v.Field(i))
+		if isEmptyContent(value) {
+			continue
+		}
+		content[name] = value
+	}
+
+	return content
+}
+
+func isEmptyContent(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+
+	return false
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type testFilter interface{ isTestFilter() }
+
+type testFilterMemberPrefix struct{ Value string }
+
+func (testFilterMemberPrefix) isTestFilter() {}
+
+type testOutput struct {
+	Name           *string
+	Enabled        *bool
+	Count          int32
+	Tags           map[string]string
+	Rules          []string
+	Filter         testFilter
+	Created        *time.Time
+	Renamed        string `json:"renamed"`
+	Skipped        string `json:"-"`
+	ResultMetadata struct{ RequestId string }
+
+	internal string
+}
+
+func TestCanonicalContent(t *testing.T) {
+	name := "bucket"
+	enabled := false
+	created := time.Date(2024, 3, 1, 10, 30, 0, 0, time.FixedZone("UTC+8", 8*60*60))
+
+	tests := []struct {
+		name  string
+		input any
+		want  string
+	}{
+		{name: "nil", input: nil, want: `null`},
+		{name: "empty struct", input: testOutput{}, want: `{"Count":0}`},
+		{
+			name: "values kept",
+			input: testOutput{
+				Name:    &name,
+				Enabled: &enabled,
+				Count:   2,
+				Tags:    map[string]string{"env": "prod"},
+				Rules:   []string{"a", "b"},
+			},
+			want: `{"Count":2,"Enabled":false,"Name":"bucket","Rules":["a","b"],"Tags":{"env":"prod"}}`,
+		},
+		{
+			name: "empty values omitted",
+			input: testOutput{
+				Name:  new(string),
+				Tags:  map[string]string{},
+				Rules: []string{},
+			},
+			want: `{"Count":0}`,
+		},
+		{
+			name:  "union member keyed by member name",
+			input: testOutput{Filter: testFilterMemberPrefix{Value: "logs/"}},
+			want:  `{"Count":0,"Filter":{"Prefix":"logs/"}}`,
+		},
+		{
+			name:  "timestamp in utc",
+			input: testOutput{Created: &created},
+			want:  `{"Count":0,"Created":"2024-03-01T02:30:00Z"}`,
+		},
+		{
+			name: "json tags, metadata and unexported fields",
+			input: testOutput{
+				Renamed:        "tagged",
+				Skipped:        "skipped",
+				ResultMetadata: struct{ RequestId string }{"request"},
+				internal:       "internal",
+			},
+			want: `{"Count":0,"renamed":"tagged"}`,
+		},
+		{
+			name:  "slice of pointers",
+			input: []*string{&name, nil},
+			want:  `["bucket",null]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(CanonicalContent(tt.input))
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("CanonicalContent() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/go-hclog"
 	"github.com/liuminhaw/mist-miner/shared"
 )
 
@@ -39,14 +41,20 @@ func NewCrawler(
 	return constructor(ctx, serviceClient)
 }
 
+// PropsCrawler reads properties of a single type. Crawlers embed CrawlerLogger, which
+// GetProperties sets with SetLogger.
 type PropsCrawler interface {
 	PropertyType() string
 	FetchConf(any) error
 	Generate(CacheInfo) ([]shared.MinerProperty, error)
+	SetLogger(hclog.Logger)
 }
 
 type PropsCrawlerConstructor func(serviceClient Client) (PropsCrawler, error)
 
+// GetProperties reads the properties of the resource with each property crawler. A failing
+// crawler does not stop the others, the resource is then returned within a PropsError keeping
+// the properties read along with a MiningError property by failed property type.
 func GetProperties(
 	serviceClient Client,
 	identifier string,
@@ -56,28 +64,57 @@ func GetProperties(
 	resource := shared.MinerResource{
 		Identifier: identifier,
 	}
+	run := clientRun(serviceClient)
+	logger := hclog.Default().With("resource", identifier)
 
+	failedTypes, failedErrs := []string{}, []error{}
+	failures := []shared.MinerProperty{}
 	for _, constructor := range constructors {
 		propsCrawler, err := constructor(serviceClient)
 		if err != nil {
 			return shared.MinerResource{}, fmt.Errorf("GetProperties(%s): %w", identifier, err)
 		}
 		propertyType := propsCrawler.PropertyType()
-		log.Printf("%s property: %s\n", identifier, propertyType)
+		propertyLogger := logger.With("property", propertyType)
+		propertyLogger.Debug("get property")
+		propsCrawler.SetLogger(propertyLogger)
 
+		start := time.Now()
 		genProps, err := propsCrawler.Generate(datum)
+		run.AddPropertyTypeDuration(propertyType, time.Since(start))
 		if err != nil {
 			var configErr *MMError
 			if errors.As(err, &configErr) {
-				log.Printf("No %s configuration found", propertyType)
+				propertyLogger.Debug("no configuration found")
+			} else if IsVanished(err) {
+				// The entity was deleted after being listed, its other properties are gone too
+				propertyLogger.Info("vanished during mining", ErrorArgs(err)...)
+				return shared.MinerResource{}, &MMError{identifier, Vanished}
 			} else {
-				return shared.MinerResource{}, fmt.Errorf("GetProperties(%s): %w", identifier, err)
+				failure, propertyErr := MiningErrorProperty(propertyType, err)
+				if propertyErr != nil {
+					return shared.MinerResource{}, fmt.Errorf(
+						"GetProperties(%s): %w", identifier, propertyErr,
+					)
+				}
+				failedTypes = append(failedTypes, propertyType)
+				failedErrs = append(failedErrs, err)
+				failures = append(failures, failure)
 			}
 		} else {
 			resource.Properties = append(resource.Properties, genProps...)
 		}
 	}
 
+	if len(failures) > 0 {
+		return shared.MinerResource{}, &PropsError{
+			Identifier:   identifier,
+			PropertyType: strings.Join(failedTypes, ","),
+			Err:          errors.Join(failedErrs...),
+			Properties:   append(resource.Properties, failures...),
+		}
+	}
+
 	// Check if there are any properties
 	if resource.Properties == nil || len(resource.Properties) == 0 {
 		return shared.MinerResource{}, &MMError{identifier, NoProps}
@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	"github.com/liuminhaw/mist-miner/shared"
+)
+
+type testClient struct{}
+
+func (testClient) Service() string { return "test" }
+
+// testCrawler generates a property labelled by its type, or fails with err
+type testCrawler struct {
+	CrawlerLogger
+
+	propertyType string
+	err          error
+}
+
+func (c testCrawler) PropertyType() string { return c.propertyType }
+
+func (c testCrawler) FetchConf(any) error { return nil }
+
+func (c testCrawler) Generate(CacheInfo) ([]shared.MinerProperty, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return []shared.MinerProperty{{
+		Type:    c.propertyType,
+		Label:   shared.MinerPropertyLabel{Name: c.propertyType, Unique: true},
+		Content: shared.MinerPropertyContent{Format: shared.FormatJson, Value: `{}`},
+	}}, nil
+}
+
+func testConstructors(crawlers ...testCrawler) []PropsCrawlerConstructor {
+	constructors := []PropsCrawlerConstructor{}
+	for _, crawler := range crawlers {
+		constructors = append(constructors, func(Client) (PropsCrawler, error) {
+			return &crawler, nil
+		})
+	}
+	return constructors
+}
+
+func TestGetProperties(t *testing.T) {
+	accessDenied := &smithy.GenericAPIError{Code: "AccessDenied"}
+
+	tests := []struct {
+		name            string
+		crawlers        []testCrawler
+		wantProperties  []string
+		wantFailedTypes string
+		wantCode        string
+	}{
+		{
+			name:           "all read",
+			crawlers:       []testCrawler{{propertyType: "Policy"}, {propertyType: "Tags"}},
+			wantProperties: []string{"Policy|Policy", "Tags|Tags"},
+		},
+		{
+			name: "no configuration",
+			crawlers: []testCrawler{
+				{propertyType: "Policy", err: &MMError{"Policy", NoConfig}},
+				{propertyType: "Tags"},
+			},
+			wantProperties: []string{"Tags|Tags"},
+		},
+		{
+			name: "failures keep the properties read",
+			crawlers: []testCrawler{
+				{propertyType: "Encryption", err: accessDenied},
+				{propertyType: "Policy"},
+				{propertyType: "Tags", err: errors.New("timeout")},
+			},
+			wantProperties: []string{
+				MiningError + "|Encryption",
+				MiningError + "|Tags",
+				"Policy|Policy",
+			},
+			wantFailedTypes: "Encryption,Tags",
+		},
+		{
+			name: "vanished",
+			crawlers: []testCrawler{
+				{propertyType: "Policy", err: accessDenied},
+				{propertyType: "Tags", err: &smithy.GenericAPIError{Code: "NoSuchEntity"}},
+			},
+			wantCode: Vanished,
+		},
+		{
+			name:     "no properties",
+			crawlers: []testCrawler{{propertyType: "Tags", err: &MMError{"Tags", NoConfig}}},
+			wantCode: NoProps,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource, err := GetProperties(
+				testClient{},
+				"entity",
+				CacheInfo{},
+				testConstructors(tt.crawlers...),
+			)
+
+			var configErr *MMError
+			var propsErr *PropsError
+			switch {
+			case tt.wantCode != "":
+				if !errors.As(err, &configErr) || configErr.Code != tt.wantCode {
+					t.Fatalf("GetProperties() error = %v, want %s", err, tt.wantCode)
+				}
+				return
+			case tt.wantFailedTypes != "":
+				if !errors.As(err, &propsErr) {
+					t.Fatalf("GetProperties() error = %v, want PropsError", err)
+				}
+				if propsErr.PropertyType != tt.wantFailedTypes {
+					t.Errorf("PropertyType = %s, want %s", propsErr.PropertyType, tt.wantFailedTypes)
+				}
+				if !errors.Is(err, accessDenied) {
+					t.Errorf("GetProperties() error = %v, want wrapping %v", err, accessDenied)
+				}
+				resource, err = propsErr.Resource()
+				if err != nil {
+					t.Fatalf("Resource() error = %v", err)
+				}
+			case err != nil:
+				t.Fatalf("GetProperties() error = %v", err)
+			}
+
+			properties := []string{}
+			for _, property := range resource.Properties {
+				properties = append(properties, property.Type+"|"+property.Label.Name)
+			}
+			if !slices.Equal(properties, tt.wantProperties) {
+				t.Errorf("properties = %v, want %v", properties, tt.wantProperties)
+			}
+		})
+	}
+}
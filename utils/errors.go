@@ -1,10 +1,21 @@
 package utils
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/aws/smithy-go"
+	"github.com/hashicorp/go-hclog"
+	"github.com/liuminhaw/mist-miner/shared"
+)
 
 const (
 	NoProps  = "NoProperties"
 	NoConfig = "NoConfiguration"
+	// Vanished is the code of an entity deleted between its listing and the reading of
+	// its properties
+	Vanished = "Vanished"
 )
 
 type MMError struct {
@@ -15,3 +26,118 @@ type MMError struct {
 func (e *MMError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Category, e.Code)
 }
+
+// vanishedErrorCodes are the api error codes of an entity that does not exist (anymore)
+var vanishedErrorCodes = map[string]struct{}{
+	"NoSuchEntity": {},
+	"NoSuchBucket": {},
+}
+
+// IsVanished reports whether err tells the requested entity does not exist, which for a
+// listed entity means it was deleted during mining
+func IsVanished(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	_, ok := vanishedErrorCodes[apiErr.ErrorCode()]
+	return ok
+}
+
+// VanishedEntities counts the entities that vanished during mining by resource type
+type VanishedEntities map[string]int
+
+func (v VanishedEntities) Add(resourceType string) {
+	v[resourceType]++
+}
+
+// LogSummary logs the vanished entity counts, with a field by resource type
+func (v VanishedEntities) LogSummary(logger hclog.Logger) {
+	resourceTypes := []string{}
+	for resourceType := range v {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	sort.Strings(resourceTypes)
+
+	total := 0
+	args := []any{}
+	for _, resourceType := range resourceTypes {
+		total += v[resourceType]
+		args = append(args, resourceType, v[resourceType])
+	}
+	logger.Info("vanished during mining", append([]any{"total", total}, args...)...)
+}
+
+// MiningError is the property type recording a property that could not be read
+const MiningError = "MiningError"
+
+// PropsError is returned by GetProperties when property crawlers of the resource fail
+type PropsError struct {
+	Identifier string
+	// PropertyType is the failed property type, comma separated when several failed
+	PropertyType string
+	Err          error
+	// Properties read by GetProperties, with a MiningError property by failed property type
+	Properties []shared.MinerProperty
+}
+
+func (e *PropsError) Error() string {
+	return fmt.Sprintf("GetProperties(%s) %s: %v", e.Identifier, e.PropertyType, e.Err)
+}
+
+func (e *PropsError) Unwrap() error { return e.Err }
+
+// Resource returns the resource of the failed GetProperties, carrying the MiningError
+// property in place of the properties that could not be read
+func (e *PropsError) Resource() (shared.MinerResource, error) {
+	if e.Properties != nil {
+		resource := shared.MinerResource{Identifier: e.Identifier, Properties: e.Properties}
+		resource.Sort()
+		return resource, nil
+	}
+
+	property, err := MiningErrorProperty(e.PropertyType, e.Err)
+	if err != nil {
+		return shared.MinerResource{}, fmt.Errorf("PropsError resource: %w", err)
+	}
+
+	return shared.MinerResource{
+		Identifier: e.Identifier,
+		Properties: []shared.MinerProperty{property},
+	}, nil
+}
+
+// MiningErrorProperty records the failure of reading a property type by the failing api
+// name and error code, leaving out the error message which changes on every run
+func MiningErrorProperty(propertyType string, err error) (shared.MinerProperty, error) {
+	content := struct {
+		PropertyType string
+		Api          string
+		ErrorCode    string
+	}{PropertyType: propertyType, ErrorCode: "Unknown"}
+
+	var operationErr *smithy.OperationError
+	if errors.As(err, &operationErr) {
+		content.Api = operationErr.OperationName
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		content.ErrorCode = apiErr.ErrorCode()
+	}
+
+	property := shared.MinerProperty{
+		Type: MiningError,
+		Label: shared.MinerPropertyLabel{
+			Name:   propertyType,
+			Unique: true,
+		},
+		Content: shared.MinerPropertyContent{
+			Format: shared.FormatJson,
+		},
+	}
+	if err := property.FormatContentValue(CanonicalContent(content)); err != nil {
+		return shared.MinerProperty{}, fmt.Errorf("MiningErrorProperty: %w", err)
+	}
+
+	return property, nil
+}
@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type GraphNode struct {
+	Id    string `json:"id"`
+	Type  string `json:"type"`
+	Label string `json:"label"`
+}
+
+type GraphEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Type   string `json:"type"`
+}
+
+// Graph is a directed graph with typed nodes and edges. Nodes and edges are
+// deduplicated on insert and kept sorted so that the serialized form is stable
+// between mining runs.
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+
+	nodeIndex map[string]int
+	edgeIndex map[GraphEdge]bool
+}
+
+func NewGraph() *Graph {
+	return &Graph{
+		Nodes:     []GraphNode{},
+		Edges:     []GraphEdge{},
+		nodeIndex: map[string]int{},
+		edgeIndex: map[GraphEdge]bool{},
+	}
+}
+
+// AddNode adds a node to the graph, ignoring nodes with an empty id. If a node with
+// the same id already exists, empty type or label of the existing node are filled in
+// from the given node.
+func (g *Graph) AddNode(node GraphNode) {
+	if node.Id == "" {
+		return
+	}
+	if i, ok := g.nodeIndex[node.Id]; ok {
+		if g.Nodes[i].Type == "" {
+			g.Nodes[i].Type = node.Type
+		}
+		if g.Nodes[i].Label == "" {
+			g.Nodes[i].Label = node.Label
+		}
+		return
+	}
+
+	g.nodeIndex[node.Id] = len(g.Nodes)
+	g.Nodes = append(g.Nodes, node)
+}
+
+func (g *Graph) HasNode(id string) bool {
+	_, ok := g.nodeIndex[id]
+	return ok
+}
+
+// AddEdge adds an edge to the graph, ignoring duplicates and edges with
+// an empty source or target.
+func (g *Graph) AddEdge(edge GraphEdge) {
+	if edge.Source == "" || edge.Target == "" || g.edgeIndex[edge] {
+		return
+	}
+
+	g.edgeIndex[edge] = true
+	g.Edges = append(g.Edges, edge)
+}
+
+// Sort orders nodes by id and edges by source, type and target.
+func (g *Graph) Sort() {
+	sort.SliceStable(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].Id < g.Nodes[j].Id
+	})
+	for i, node := range g.Nodes {
+		g.nodeIndex[node.Id] = i
+	}
+
+	sort.SliceStable(g.Edges, func(i, j int) bool {
+		if g.Edges[i].Source == g.Edges[j].Source {
+			if g.Edges[i].Type == g.Edges[j].Type {
+				return g.Edges[i].Target < g.Edges[j].Target
+			}
+			return g.Edges[i].Type < g.Edges[j].Type
+		}
+		return g.Edges[i].Source < g.Edges[j].Source
+	})
+}
+
+// DOT renders the graph in Graphviz DOT format.
+func (g *Graph) DOT(name string) string {
+	g.Sort()
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "digraph %s {\n", dotQuote(name))
+	builder.WriteString("  rankdir=LR;\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(
+			&builder,
+			"  %s [label=%s, shape=%s];\n",
+			dotQuote(node.Id),
+			dotQuote(fmt.Sprintf("%s\n%s", node.Type, node.Label)),
+			dotShape(node.Type),
+		)
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(
+			&builder,
+			"  %s -> %s [label=%s];\n",
+			dotQuote(edge.Source),
+			dotQuote(edge.Target),
+			dotQuote(edge.Type),
+		)
+	}
+	builder.WriteString("}\n")
+
+	return builder.String()
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return fmt.Sprintf(`"%s"`, s)
+}
+
+func dotShape(nodeType string) string {
+	switch strings.ToLower(nodeType) {
+	case "policy":
+		return "note"
+	case "group":
+		return "folder"
+	case "role":
+		return "hexagon"
+	default:
+		return "box"
+	}
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/logging"
+	"github.com/hashicorp/go-hclog"
+	"github.com/liuminhaw/mist-miner/shared"
+)
+
+const LogEquipmentType = "log"
+
+// NewLogger creates the logger of the plugin run and makes it the default hclog logger.
+// Lines are written to stderr as json, for go-plugin to forward them with their level,
+// and carry the plugin field. The level is read from the equipment "log" "stderr",
+// Info by default. Lines of the standard log package go through the logger as well.
+func NewLogger(plugin string, equipments []shared.MinerConfigEquipment) hclog.Logger {
+	value := GetEquipAttribute(
+		equipments,
+		EquipmentInfo{
+			TargetType: LogEquipmentType,
+			TargetName: "stderr",
+			TargetAttr: "level",
+			DefaultVal: hclog.Info.String(),
+		},
+	)
+	level := hclog.LevelFromString(value)
+	invalidLevel := level == hclog.NoLevel
+	if invalidLevel {
+		level = hclog.Info
+	}
+
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:       plugin,
+		Level:      level,
+		Output:     os.Stderr,
+		JSONFormat: true,
+	}).With("plugin", plugin)
+	if invalidLevel {
+		logger.Warn("invalid log level, use default", "level", value, "default", hclog.Info.String())
+	}
+
+	hclog.SetDefault(logger)
+	log.SetOutput(logger.StandardWriter(&hclog.StandardLoggerOptions{InferLevels: true}))
+	log.SetFlags(0)
+
+	return logger
+}
+
+// ErrorArgs returns the log fields of err: the error itself, and the aws service and
+// operation of the failed api call when known
+func ErrorArgs(err error) []any {
+	args := []any{"error", err}
+
+	var operationErr *smithy.OperationError
+	if errors.As(err, &operationErr) {
+		args = append(args, "service", operationErr.ServiceID, "operation", operationErr.OperationName)
+	}
+
+	return args
+}
+
+// LoggerConfig returns a copy of cfg logging the sdk retries through logger, with the
+// aws service and operation fields of the retried api call
+func LoggerConfig(cfg aws.Config, logger hclog.Logger) aws.Config {
+	cfg = cfg.Copy()
+	cfg.Logger = sdkLogger{logger: logger}
+	cfg.ClientLogMode |= aws.LogRetries
+
+	return cfg
+}
+
+// sdkLogger adapts hclog to the aws sdk logger
+type sdkLogger struct {
+	logger hclog.Logger
+}
+
+func (l sdkLogger) Logf(classification logging.Classification, format string, v ...any) {
+	if classification == logging.Warn {
+		l.logger.Warn(fmt.Sprintf(format, v...))
+		return
+	}
+	l.logger.Debug(fmt.Sprintf(format, v...))
+}
+
+func (l sdkLogger) WithContext(ctx context.Context) logging.Logger {
+	return sdkLogger{logger: l.logger.With(
+		"service", awsmiddleware.GetServiceID(ctx),
+		"operation", awsmiddleware.GetOperationName(ctx),
+	)}
+}
+
+// CrawlerLogger is embedded by the property crawlers, GetProperties sets it to a logger
+// carrying the resource identifier and property type fields
+type CrawlerLogger struct {
+	logger hclog.Logger
+}
+
+func (c *CrawlerLogger) SetLogger(logger hclog.Logger) { c.logger = logger }
+
+// Logger returns the logger set by GetProperties, the default logger otherwise
+func (c *CrawlerLogger) Logger() hclog.Logger {
+	if c.logger == nil {
+		return hclog.Default()
+	}
+	return c.logger
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// Partition is an aws partition with its default region, the region of the buckets without
+// location constraint.
+type Partition struct {
+	Name          string
+	DefaultRegion string
+}
+
+var (
+	PartitionAws      = Partition{Name: "aws", DefaultRegion: "us-east-1"}
+	PartitionAwsUsGov = Partition{Name: "aws-us-gov", DefaultRegion: "us-gov-west-1"}
+	PartitionAwsCn    = Partition{Name: "aws-cn", DefaultRegion: "cn-north-1"}
+	PartitionAwsIso   = Partition{Name: "aws-iso", DefaultRegion: "us-iso-east-1"}
+	PartitionAwsIsoB  = Partition{Name: "aws-iso-b", DefaultRegion: "us-isob-east-1"}
+)
+
+var partitions = []Partition{
+	PartitionAws, PartitionAwsUsGov, PartitionAwsCn, PartitionAwsIso, PartitionAwsIsoB,
+}
+
+// Arn builds an arn in the partition, e.g. arn:aws-us-gov:iam::aws:policy/<name>
+func (p Partition) Arn(service, region, account, resource string) string {
+	return strings.Join([]string{"arn", p.Name, service, region, account, resource}, ":")
+}
+
+// RegionPartition returns the partition of a region, aws when not recognized
+func RegionPartition(region string) Partition {
+	switch {
+	case strings.HasPrefix(region, "us-gov-"):
+		return PartitionAwsUsGov
+	case strings.HasPrefix(region, "cn-"):
+		return PartitionAwsCn
+	case strings.HasPrefix(region, "us-isob-"):
+		return PartitionAwsIsoB
+	case strings.HasPrefix(region, "us-iso-"):
+		return PartitionAwsIso
+	}
+	return PartitionAws
+}
+
+// ArnPartition returns the partition of an arn, aws when not recognized
+func ArnPartition(arn string) Partition {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 2 || parts[0] != "arn" {
+		return PartitionAws
+	}
+	for _, partition := range partitions {
+		if partition.Name == parts[1] {
+			return partition
+		}
+	}
+	return PartitionAws
+}
+
+// ErrNoRegion is returned by CallerIdentity when the config has no region, the partition
+// of the credentials and therefore its sts endpoint are not known without one
+var ErrNoRegion = errors.New(
+	"no region configured, set the region of the profile or the AWS_REGION environment variable",
+)
+
+// CallerIdentity gets the account id and the partition of the configured credentials from
+// sts GetCallerIdentity, called in the config region.
+func CallerIdentity(cfg aws.Config) (string, Partition, error) {
+	if cfg.Region == "" {
+		return "", PartitionAws, fmt.Errorf("CallerIdentity: %w", ErrNoRegion)
+	}
+
+	identity, err := sts.NewFromConfig(cfg).GetCallerIdentity(
+		context.Background(),
+		&sts.GetCallerIdentityInput{},
+	)
+	if err != nil {
+		return "", RegionPartition(cfg.Region), fmt.Errorf("CallerIdentity: %w", err)
+	}
+
+	return aws.ToString(identity.Account), ArnPartition(aws.ToString(identity.Arn)), nil
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestRegionPartition(t *testing.T) {
+	tests := map[string]Partition{
+		"us-east-1":      PartitionAws,
+		"eu-west-3":      PartitionAws,
+		"us-gov-west-1":  PartitionAwsUsGov,
+		"us-gov-east-1":  PartitionAwsUsGov,
+		"cn-north-1":     PartitionAwsCn,
+		"cn-northwest-1": PartitionAwsCn,
+		"us-iso-east-1":  PartitionAwsIso,
+		"us-isob-east-1": PartitionAwsIsoB,
+		"":               PartitionAws,
+	}
+	for region, want := range tests {
+		if got := RegionPartition(region); got != want {
+			t.Errorf("RegionPartition(%q) = %s, want %s", region, got.Name, want.Name)
+		}
+	}
+}
+
+func TestArnPartition(t *testing.T) {
+	tests := map[string]Partition{
+		"arn:aws:iam::111122223333:user/reader":         PartitionAws,
+		"arn:aws-us-gov:iam::111122223333:root":         PartitionAwsUsGov,
+		"arn:aws-cn:sts::111122223333:assumed-role/a/b": PartitionAwsCn,
+		"arn:aws-iso:iam::111122223333:root":            PartitionAwsIso,
+		"arn:aws-iso-b:iam::111122223333:root":          PartitionAwsIsoB,
+		"arn:aws-unknown:iam::111122223333:root":        PartitionAws,
+		"111122223333":                                  PartitionAws,
+		"":                                              PartitionAws,
+	}
+	for arn, want := range tests {
+		if got := ArnPartition(arn); got != want {
+			t.Errorf("ArnPartition(%q) = %s, want %s", arn, got.Name, want.Name)
+		}
+	}
+}
+
+func TestPartitionArn(t *testing.T) {
+	got := PartitionAwsUsGov.Arn("iam", "", "aws", "policy/AWSSupportAccess")
+	want := "arn:aws-us-gov:iam::aws:policy/AWSSupportAccess"
+	if got != want {
+		t.Errorf("Arn() = %s, want %s", got, want)
+	}
+}
+
+func TestCallerIdentityNoRegion(t *testing.T) {
+	_, _, err := CallerIdentity(aws.Config{})
+	if !errors.Is(err, ErrNoRegion) {
+		t.Errorf("CallerIdentity() error = %v, want %v", err, ErrNoRegion)
+	}
+}
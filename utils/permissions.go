@@ -0,0 +1,250 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/hashicorp/go-hclog"
+	"github.com/liuminhaw/mist-miner/shared"
+)
+
+const PermissionsEquipmentType = "permissions"
+
+// RequiredPermissionsResource is the identifier of the resource holding the iam policy
+// needed by a plugin run and the permissions found missing by the preflight
+const RequiredPermissionsResource = "RequiredPermissions"
+
+// RequiredPermissions resource property types
+const (
+	PermissionsPolicyDocument = "PolicyDocument"
+	PermissionsMissing        = "MissingPermissions"
+)
+
+const policyVersion = "2012-10-17"
+
+// PreflightActions are the actions needed by the preflight itself
+var PreflightActions = []string{"iam:GetRole", "iam:SimulatePrincipalPolicy"}
+
+// Permissions is the set of iam actions, <service>:<action>, needed by a plugin run
+type Permissions map[string]struct{}
+
+func (p Permissions) Add(actions ...string) {
+	for _, action := range actions {
+		p[action] = struct{}{}
+	}
+}
+
+// AddCrawlers adds the actions of the property crawlers built by constructors, looked up
+// by property type in catalog. A property type missing from the catalog is an error, for
+// a new crawler not to be left out of the policy unnoticed.
+func (p Permissions) AddCrawlers(
+	serviceClient Client,
+	constructors []PropsCrawlerConstructor,
+	catalog map[string][]string,
+) error {
+	for _, constructor := range constructors {
+		crawler, err := constructor(serviceClient)
+		if err != nil {
+			return fmt.Errorf("AddCrawlers: %w", err)
+		}
+		actions, ok := catalog[crawler.PropertyType()]
+		if !ok {
+			return fmt.Errorf("AddCrawlers: no actions of property type %s", crawler.PropertyType())
+		}
+		p.Add(actions...)
+	}
+
+	return nil
+}
+
+// Actions returns the actions in sorted order
+func (p Permissions) Actions() []string {
+	actions := []string{}
+	for action := range p {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	return actions
+}
+
+// Document returns the policy document allowing the actions on every resource, with a
+// statement by service
+func (p Permissions) Document() PolicyDocument {
+	services := map[string][]string{}
+	for _, action := range p.Actions() {
+		service := strings.SplitN(action, ":", 2)[0]
+		services[service] = append(services[service], action)
+	}
+	names := []string{}
+	for service := range services {
+		names = append(names, service)
+	}
+	sort.Strings(names)
+
+	document := PolicyDocument{Version: policyVersion, Statement: PolicyStatements{}}
+	for _, service := range names {
+		document.Statement = append(document.Statement, PolicyStatement{
+			Sid:      fmt.Sprintf("MistMiner%s", strings.ToUpper(service[:1])+service[1:]),
+			Effect:   "Allow",
+			Action:   StringOrSlice(services[service]),
+			Resource: StringOrSlice{"*"},
+		})
+	}
+
+	return document
+}
+
+// PermissionsCheck is the permissions setting of a plugin run, read from the equipments
+// "permissions" "policy" and "permissions" "preflight", both disabled by default
+type PermissionsCheck struct {
+	// Policy emits the policy document of the needed actions
+	Policy bool
+	// Preflight simulates the needed actions against the policies of the caller
+	Preflight bool
+}
+
+func NewPermissionsCheck(equipments []shared.MinerConfigEquipment) PermissionsCheck {
+	mode := func(name string) bool {
+		return GetEquipAttribute(
+			equipments,
+			EquipmentInfo{
+				TargetType: PermissionsEquipmentType,
+				TargetName: name,
+				TargetAttr: "mode",
+				DefaultVal: "Disabled",
+				AcceptVals: []string{"Enabled", "Disabled"},
+			},
+		) == "Enabled"
+	}
+
+	return PermissionsCheck{Policy: mode("policy"), Preflight: mode("preflight")}
+}
+
+func (c PermissionsCheck) Enabled() bool {
+	return c.Policy || c.Preflight
+}
+
+// Resource builds the RequiredPermissions resource of the actions. The preflight failing,
+// for instance on a caller not allowed to simulate its own policies, is recorded as a
+// MiningError property and does not stop the run.
+func (c PermissionsCheck) Resource(
+	ctx context.Context,
+	cfg aws.Config,
+	permissions Permissions,
+	logger hclog.Logger,
+) (shared.MinerResource, error) {
+	if c.Preflight {
+		permissions.Add(PreflightActions...)
+	}
+	resource := shared.MinerResource{Identifier: RequiredPermissionsResource}
+
+	if c.Policy {
+		property, err := permissionsProperty(
+			PermissionsPolicyDocument,
+			PermissionsPolicyDocument,
+			permissions.Document(),
+		)
+		if err != nil {
+			return shared.MinerResource{}, fmt.Errorf("RequiredPermissions resource: %w", err)
+		}
+		resource.Properties = append(resource.Properties, property)
+		logger.Info("required permissions", "actions", len(permissions))
+	}
+
+	if c.Preflight {
+		principal, missing, err := Preflight(ctx, cfg, permissions.Actions())
+		var property shared.MinerProperty
+		if err != nil {
+			logger.Warn("failed to preflight permissions", ErrorArgs(err)...)
+			property, err = MiningErrorProperty(PermissionsMissing, err)
+		} else {
+			if len(missing) > 0 {
+				logger.Warn("missing permissions", "principal", principal, "actions", missing)
+			}
+			property, err = permissionsProperty(PermissionsMissing, PermissionsMissing, struct {
+				Principal string
+				Actions   []string
+			}{principal, missing})
+		}
+		if err != nil {
+			return shared.MinerResource{}, fmt.Errorf("RequiredPermissions resource: %w", err)
+		}
+		resource.Properties = append(resource.Properties, property)
+	}
+
+	resource.Sort()
+	return resource, nil
+}
+
+func permissionsProperty(propertyType, label string, content any) (shared.MinerProperty, error) {
+	property := shared.MinerProperty{
+		Type: propertyType,
+		Label: shared.MinerPropertyLabel{
+			Name:   label,
+			Unique: true,
+		},
+		Content: shared.MinerPropertyContent{
+			Format: shared.FormatJson,
+		},
+	}
+	if err := property.FormatContentValue(CanonicalContent(content)); err != nil {
+		return shared.MinerProperty{}, fmt.Errorf("%s property: %w", propertyType, err)
+	}
+
+	return property, nil
+}
+
+// Preflight simulates the actions against the identity based policies of the caller of
+// cfg with iam SimulatePrincipalPolicy, and returns the simulated principal arn and the
+// actions not allowed. A role session is simulated as its role, for the arn not to change
+// with the session name. Resource based policies are not evaluated, the actions are
+// simulated on every resource. The root user is allowed every action and is not simulated.
+func Preflight(ctx context.Context, cfg aws.Config, actions []string) (string, []string, error) {
+	identity, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", nil, fmt.Errorf("Preflight: %w", err)
+	}
+	principal := aws.ToString(identity.Arn)
+	if strings.HasSuffix(principal, ":root") {
+		return principal, []string{}, nil
+	}
+
+	client := iam.NewFromConfig(cfg)
+	// The role path is not part of the session arn and is read from the role
+	if resource := strings.SplitN(principal, ":", 6); len(resource) == 6 &&
+		resource[2] == "sts" && strings.HasPrefix(resource[5], "assumed-role/") {
+		roleName := strings.Split(resource[5], "/")[1]
+		role, err := client.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String(roleName)})
+		if err != nil {
+			return principal, nil, fmt.Errorf("Preflight: %w", err)
+		}
+		principal = aws.ToString(role.Role.Arn)
+	}
+
+	missing := []string{}
+	paginator := iam.NewSimulatePrincipalPolicyPaginator(client, &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principal),
+		ActionNames:     actions,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return principal, nil, fmt.Errorf("Preflight: %w", err)
+		}
+		for _, result := range page.EvaluationResults {
+			if result.EvalDecision != types.PolicyEvaluationDecisionTypeAllowed {
+				missing = append(missing, aws.ToString(result.EvalActionName))
+			}
+		}
+	}
+	sort.Strings(missing)
+
+	return principal, missing, nil
+}
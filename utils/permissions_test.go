@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPermissionsDocument(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []string
+		want    PolicyStatements
+	}{
+		{name: "no actions", want: PolicyStatements{}},
+		{
+			name:    "single service",
+			actions: []string{"sso:ListInstances", "sso:DescribePermissionSet", "sso:ListInstances"},
+			want: PolicyStatements{{
+				Sid:      "MistMinerSso",
+				Effect:   "Allow",
+				Action:   StringOrSlice{"sso:DescribePermissionSet", "sso:ListInstances"},
+				Resource: StringOrSlice{"*"},
+			}},
+		},
+		{
+			name: "statement by service",
+			actions: []string{
+				"sso:ListInstances",
+				"identitystore:ListUsers",
+				"iam:SimulatePrincipalPolicy",
+				"identitystore:ListGroups",
+				"iam:GetRole",
+			},
+			want: PolicyStatements{
+				{
+					Sid:      "MistMinerIam",
+					Effect:   "Allow",
+					Action:   StringOrSlice{"iam:GetRole", "iam:SimulatePrincipalPolicy"},
+					Resource: StringOrSlice{"*"},
+				},
+				{
+					Sid:      "MistMinerIdentitystore",
+					Effect:   "Allow",
+					Action:   StringOrSlice{"identitystore:ListGroups", "identitystore:ListUsers"},
+					Resource: StringOrSlice{"*"},
+				},
+				{
+					Sid:      "MistMinerSso",
+					Effect:   "Allow",
+					Action:   StringOrSlice{"sso:ListInstances"},
+					Resource: StringOrSlice{"*"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			permissions := Permissions{}
+			permissions.Add(tt.actions...)
+
+			got := permissions.Document()
+			if got.Version != policyVersion {
+				t.Errorf("Document() version = %s, want %s", got.Version, policyVersion)
+			}
+			if !reflect.DeepEqual(got.Statement, tt.want) {
+				t.Errorf("Document() statements = %+v, want %+v", got.Statement, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermissionsAddCrawlers(t *testing.T) {
+	constructors := testConstructors(testCrawler{propertyType: "Policy"}, testCrawler{propertyType: "Tags"})
+	tests := []struct {
+		name    string
+		catalog map[string][]string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "catalog complete",
+			catalog: map[string][]string{"Policy": {"iam:GetPolicy"}, "Tags": {}},
+			want:    []string{"iam:GetPolicy"},
+		},
+		{name: "property type missing", catalog: map[string][]string{"Policy": {"iam:GetPolicy"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			permissions := Permissions{}
+			err := permissions.AddCrawlers(nil, constructors, tt.catalog)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AddCrawlers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(permissions.Actions(), tt.want) {
+				t.Errorf("AddCrawlers() actions = %v, want %v", permissions.Actions(), tt.want)
+			}
+		})
+	}
+}
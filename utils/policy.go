@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// StringOrSlice unmarshals a json value that can be either a single string
+// or an array of strings, as found in IAM policy documents.
+type StringOrSlice []string
+
+func (s *StringOrSlice) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*s = StringOrSlice{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return fmt.Errorf("StringOrSlice unmarshal: %w", err)
+	}
+	*s = StringOrSlice(multiple)
+
+	return nil
+}
+
+// PolicyPrincipal holds the principals of a policy statement keyed by principal type
+// (AWS, Service, Federated, CanonicalUser). A wildcard principal ("*") is stored
+// under the AWS key.
+type PolicyPrincipal map[string]StringOrSlice
+
+func (p *PolicyPrincipal) UnmarshalJSON(data []byte) error {
+	var wildcard string
+	if err := json.Unmarshal(data, &wildcard); err == nil {
+		*p = PolicyPrincipal{"AWS": StringOrSlice{wildcard}}
+		return nil
+	}
+
+	principals := map[string]StringOrSlice{}
+	if err := json.Unmarshal(data, &principals); err != nil {
+		return fmt.Errorf("PolicyPrincipal unmarshal: %w", err)
+	}
+	*p = PolicyPrincipal(principals)
+
+	return nil
+}
+
+// Types returns the principal types of the statement in sorted order.
+func (p PolicyPrincipal) Types() []string {
+	types := []string{}
+	for key := range p {
+		types = append(types, key)
+	}
+	sort.Strings(types)
+
+	return types
+}
+
+type PolicyStatement struct {
+	Sid          string                    `json:"Sid,omitempty"`
+	Effect       string                    `json:"Effect"`
+	Principal    PolicyPrincipal           `json:"Principal,omitempty"`
+	NotPrincipal PolicyPrincipal           `json:"NotPrincipal,omitempty"`
+	Action       StringOrSlice             `json:"Action,omitempty"`
+	NotAction    StringOrSlice             `json:"NotAction,omitempty"`
+	Resource     StringOrSlice             `json:"Resource,omitempty"`
+	NotResource  StringOrSlice             `json:"NotResource,omitempty"`
+	Condition    map[string]map[string]any `json:"Condition,omitempty"`
+}
+
+type PolicyStatements []PolicyStatement
+
+func (s *PolicyStatements) UnmarshalJSON(data []byte) error {
+	var single PolicyStatement
+	if err := json.Unmarshal(data, &single); err == nil {
+		*s = PolicyStatements{single}
+		return nil
+	}
+
+	var multiple []PolicyStatement
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return fmt.Errorf("PolicyStatements unmarshal: %w", err)
+	}
+	*s = PolicyStatements(multiple)
+
+	return nil
+}
+
+type PolicyDocument struct {
+	Version   string           `json:"Version,omitempty"`
+	Id        string           `json:"Id,omitempty"`
+	Statement PolicyStatements `json:"Statement"`
+}
+
+// ParsePolicyDocument parses a json formatted (already url decoded) policy document.
+func ParsePolicyDocument(content string) (PolicyDocument, error) {
+	var document PolicyDocument
+	if err := json.Unmarshal([]byte(content), &document); err != nil {
+		return PolicyDocument{}, fmt.Errorf("ParsePolicyDocument: %w", err)
+	}
+
+	return document, nil
+}
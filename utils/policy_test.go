@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestStringOrSliceUnmarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    StringOrSlice
+		wantErr bool
+	}{
+		{name: "single string", input: `"s3:GetObject"`, want: StringOrSlice{"s3:GetObject"}},
+		{
+			name:  "array",
+			input: `["s3:GetObject", "s3:PutObject"]`,
+			want:  StringOrSlice{"s3:GetObject", "s3:PutObject"},
+		},
+		{name: "empty array", input: `[]`, want: StringOrSlice{}},
+		{name: "number", input: `1`, wantErr: true},
+		{name: "object", input: `{"a": "b"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got StringOrSlice
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal(%s) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unmarshal(%s) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePolicyDocument(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    PolicyDocument
+		wantErr bool
+	}{
+		{
+			name: "single statement object",
+			content: `{
+				"Version": "2012-10-17",
+				"Statement": {
+					"Effect": "Allow",
+					"Action": "iam:GetUser",
+					"Resource": "*"
+				}
+			}`,
+			want: PolicyDocument{
+				Version: "2012-10-17",
+				Statement: PolicyStatements{{
+					Effect:   "Allow",
+					Action:   StringOrSlice{"iam:GetUser"},
+					Resource: StringOrSlice{"*"},
+				}},
+			},
+		},
+		{
+			name: "trust policy with principals",
+			content: `{
+				"Version": "2012-10-17",
+				"Statement": [
+					{
+						"Sid": "Trust",
+						"Effect": "Allow",
+						"Principal": {"Service": "ec2.amazonaws.com", "AWS": ["arn:aws:iam::123456789012:root"]},
+						"Action": "sts:AssumeRole"
+					},
+					{
+						"Effect": "Deny",
+						"Principal": "*",
+						"NotAction": ["sts:TagSession"],
+						"Condition": {"Bool": {"aws:SecureTransport": "false"}}
+					}
+				]
+			}`,
+			want: PolicyDocument{
+				Version: "2012-10-17",
+				Statement: PolicyStatements{
+					{
+						Sid:    "Trust",
+						Effect: "Allow",
+						Principal: PolicyPrincipal{
+							"Service": StringOrSlice{"ec2.amazonaws.com"},
+							"AWS":     StringOrSlice{"arn:aws:iam::123456789012:root"},
+						},
+						Action: StringOrSlice{"sts:AssumeRole"},
+					},
+					{
+						Effect:    "Deny",
+						Principal: PolicyPrincipal{"AWS": StringOrSlice{"*"}},
+						NotAction: StringOrSlice{"sts:TagSession"},
+						Condition: map[string]map[string]any{
+							"Bool": {"aws:SecureTransport": "false"},
+						},
+					},
+				},
+			},
+		},
+		{name: "invalid json", content: `{"Statement": [`, wantErr: true},
+		{name: "invalid action", content: `{"Statement": {"Action": 1}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePolicyDocument(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePolicyDocument() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePolicyDocument() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyPrincipalTypes(t *testing.T) {
+	principal := PolicyPrincipal{
+		"Service":   StringOrSlice{"lambda.amazonaws.com"},
+		"AWS":       StringOrSlice{"*"},
+		"Federated": StringOrSlice{"cognito-identity.amazonaws.com"},
+	}
+	want := []string{"AWS", "Federated", "Service"}
+	if got := principal.Types(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Types() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,231 @@
+package utils
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/hashicorp/go-hclog"
+	"github.com/liuminhaw/mist-miner/shared"
+)
+
+const RetryEquipmentType = "retry"
+
+// Retry defaults favour slowing a run down over failing it on throttling errors
+const (
+	DefaultRetryMode        = aws.RetryModeAdaptive
+	DefaultRetryMaxAttempts = 10
+	DefaultRetryMaxBackoff  = retry.DefaultMaxBackoff
+)
+
+const rateLimitMiddlewareId = "ClientRateLimit"
+
+// RetryPolicy is the retry and client side rate limit setting of a service, read from the
+// equipment "retry" "<service>". It counts the retries and throttles of the clients it is
+// applied to.
+type RetryPolicy struct {
+	Service     string
+	Mode        aws.RetryMode
+	MaxAttempts int
+	MaxBackoff  time.Duration
+	// RateLimit is the maximum requests per second sent by the clients, 0 for no limit
+	RateLimit float64
+
+	retries   atomic.Int64
+	throttles atomic.Int64
+	retryer   aws.Retryer
+	limiter   *rateLimiter
+}
+
+// NewRetryPolicy reads the retry policy of the service from equipments, falling back to the
+// defaults on missing or invalid values.
+func NewRetryPolicy(equipments []shared.MinerConfigEquipment, service string) *RetryPolicy {
+	info := func(attr, defaultVal string, acceptVals ...string) EquipmentInfo {
+		return EquipmentInfo{
+			TargetType: RetryEquipmentType,
+			TargetName: service,
+			TargetAttr: attr,
+			DefaultVal: defaultVal,
+			AcceptVals: acceptVals,
+		}
+	}
+
+	logger := hclog.Default().With("service", service)
+	policy := &RetryPolicy{
+		Service: service,
+		Mode: aws.RetryMode(GetEquipAttribute(equipments, info(
+			"mode",
+			string(DefaultRetryMode),
+			string(aws.RetryModeStandard),
+			string(aws.RetryModeAdaptive),
+		))),
+		MaxAttempts: DefaultRetryMaxAttempts,
+		MaxBackoff:  DefaultRetryMaxBackoff,
+	}
+
+	value := GetEquipAttribute(equipments, info("maxAttempts", ""))
+	if value != "" {
+		if attempts, err := strconv.Atoi(value); err == nil && attempts > 0 {
+			policy.MaxAttempts = attempts
+		} else {
+			logger.Warn(
+				"invalid retry maxAttempts, use default",
+				"maxAttempts", value, "default", DefaultRetryMaxAttempts,
+			)
+		}
+	}
+	value = GetEquipAttribute(equipments, info("maxBackoff", ""))
+	if value != "" {
+		if backoff, err := time.ParseDuration(value); err == nil && backoff > 0 {
+			policy.MaxBackoff = backoff
+		} else {
+			logger.Warn(
+				"invalid retry maxBackoff, use default",
+				"maxBackoff", value, "default", DefaultRetryMaxBackoff.String(),
+			)
+		}
+	}
+	value = GetEquipAttribute(equipments, info("rateLimit", ""))
+	if value != "" {
+		if rateLimit, err := strconv.ParseFloat(value, 64); err == nil && rateLimit > 0 {
+			policy.RateLimit = rateLimit
+		} else {
+			logger.Warn("invalid retry rateLimit, no rate limit applied", "rateLimit", value)
+		}
+	}
+
+	policy.retryer = policy.newRetryer()
+	if policy.RateLimit > 0 {
+		policy.limiter = newRateLimiter(policy.RateLimit)
+	}
+	logger.Info(
+		"retry policy",
+		"mode", policy.Mode,
+		"maxAttempts", policy.MaxAttempts,
+		"maxBackoff", policy.MaxBackoff.String(),
+		"rateLimit", policy.RateLimit,
+	)
+
+	return policy
+}
+
+// Config returns a copy of cfg with the retry policy applied. The clients built from the
+// returned configs share the retryer, and so the adaptive retry rate, and the rate limit.
+func (p *RetryPolicy) Config(cfg aws.Config) aws.Config {
+	cfg = cfg.Copy()
+	cfg.Retryer = func() aws.Retryer { return p.retryer }
+	if p.limiter != nil {
+		cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+			// Rate limit every attempt, retries included
+			return stack.Finalize.Insert(p.limiter, "Retry", middleware.After)
+		})
+	}
+
+	return cfg
+}
+
+// LogSummary logs how many retries and throttles happened with the policy
+func (p *RetryPolicy) LogSummary(logger hclog.Logger) {
+	logger.Info(
+		"retry summary",
+		"service", p.Service,
+		"retries", p.retries.Load(),
+		"throttles", p.throttles.Load(),
+		"mode", p.Mode,
+		"maxAttempts", p.MaxAttempts,
+	)
+}
+
+func (p *RetryPolicy) newRetryer() aws.Retryer {
+	standardOptions := func(o *retry.StandardOptions) {
+		o.MaxAttempts = p.MaxAttempts
+		o.MaxBackoff = p.MaxBackoff
+	}
+
+	var retryer aws.RetryerV2
+	if p.Mode == aws.RetryModeAdaptive {
+		retryer = retry.NewAdaptiveMode(func(o *retry.AdaptiveModeOptions) {
+			o.StandardOptions = append(o.StandardOptions, standardOptions)
+		})
+	} else {
+		retryer = retry.NewStandard(standardOptions)
+	}
+
+	return &countingRetryer{RetryerV2: retryer, policy: p}
+}
+
+// countingRetryer counts the retries and throttles of the wrapped retryer
+type countingRetryer struct {
+	aws.RetryerV2
+	policy *RetryPolicy
+}
+
+var throttleErrors = retry.ThrottleErrorCode{Codes: retry.DefaultThrottleErrorCodes}
+
+func (r *countingRetryer) IsErrorRetryable(err error) bool {
+	if throttleErrors.IsErrorThrottle(err) == aws.TrueTernary {
+		r.policy.throttles.Add(1)
+	}
+	return r.RetryerV2.IsErrorRetryable(err)
+}
+
+// RetryDelay is called by the retry middleware once for every retried attempt
+func (r *countingRetryer) RetryDelay(attempt int, err error) (time.Duration, error) {
+	r.policy.retries.Add(1)
+	return r.RetryerV2.RetryDelay(attempt, err)
+}
+
+// rateLimiter spaces the requests sent through it evenly to the configured rate
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(rate float64) *rateLimiter {
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / rate)}
+}
+
+func (l *rateLimiter) ID() string {
+	return rateLimitMiddlewareId
+}
+
+func (l *rateLimiter) HandleFinalize(
+	ctx context.Context,
+	in middleware.FinalizeInput,
+	next middleware.FinalizeHandler,
+) (middleware.FinalizeOutput, middleware.Metadata, error) {
+	if err := l.wait(ctx); err != nil {
+		return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+	}
+	return next.HandleFinalize(ctx, in)
+}
+
+// wait blocks until the next request slot, or until the context is done
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
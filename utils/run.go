@@ -0,0 +1,234 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/liuminhaw/mist-miner/shared"
+)
+
+// MiningRunResource is the identifier of the resource summarizing a plugin run
+const MiningRunResource = "MiningRun"
+
+// MiningRun resource property types
+const (
+	RunTime                 = "RunTime"
+	RunResourceTypeDuration = "ResourceTypeDuration"
+	RunPropertyTypeDuration = "PropertyTypeDuration"
+	RunApiCalls             = "ApiCalls"
+	RunApiErrors            = "ApiErrors"
+	RunRetries              = "Retries"
+	RunCounts               = "Counts"
+)
+
+const apiCallsMiddlewareId = "MiningRunApiCalls"
+
+// RunRecorder is implemented by the service clients recording their crawling time to a
+// mining run
+type RunRecorder interface {
+	MiningRun() *MiningRun
+}
+
+// MiningRun records what a plugin run did: the time spent by resource type and property
+// type, the api calls by operation, the api errors by code and the retries of the retry
+// policies. The methods of a nil MiningRun do nothing.
+type MiningRun struct {
+	mu            sync.Mutex
+	start         time.Time
+	resourceTypes map[string]*runDuration
+	propertyTypes map[string]*runDuration
+	apiCalls      map[string]int
+	apiErrors     map[string]int
+	retryPolicies []*RetryPolicy
+}
+
+type runDuration struct {
+	count    int
+	duration time.Duration
+}
+
+// NewMiningRun starts recording a run, the retries of the given policies are reported
+func NewMiningRun(retryPolicies ...*RetryPolicy) *MiningRun {
+	return &MiningRun{
+		start:         time.Now(),
+		resourceTypes: map[string]*runDuration{},
+		propertyTypes: map[string]*runDuration{},
+		apiCalls:      map[string]int{},
+		apiErrors:     map[string]int{},
+		retryPolicies: retryPolicies,
+	}
+}
+
+// clientRun returns the mining run of the service client, nil when it records none
+func clientRun(serviceClient Client) *MiningRun {
+	if recorder, ok := serviceClient.(RunRecorder); ok {
+		return recorder.MiningRun()
+	}
+	return nil
+}
+
+// AddResourceTypeDuration adds the time spent mining one or more resources of a type
+func (r *MiningRun) AddResourceTypeDuration(resourceType string, d time.Duration) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	addDuration(r.resourceTypes, resourceType, d)
+}
+
+// AddPropertyTypeDuration adds the time spent generating the properties of a type
+func (r *MiningRun) AddPropertyTypeDuration(propertyType string, d time.Duration) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	addDuration(r.propertyTypes, propertyType, d)
+}
+
+func addDuration(durations map[string]*runDuration, name string, d time.Duration) {
+	if _, ok := durations[name]; !ok {
+		durations[name] = &runDuration{}
+	}
+	durations[name].count++
+	durations[name].duration += d
+}
+
+// Config returns a copy of cfg counting the api calls, by <service>:<operation>, and the
+// api errors, by error code, of the clients built from it
+func (r *MiningRun) Config(cfg aws.Config) aws.Config {
+	cfg = cfg.Copy()
+	cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(
+			middleware.InitializeMiddlewareFunc(apiCallsMiddlewareId, r.countApiCall),
+			middleware.After,
+		)
+	})
+
+	return cfg
+}
+
+func (r *MiningRun) countApiCall(
+	ctx context.Context,
+	in middleware.InitializeInput,
+	next middleware.InitializeHandler,
+) (middleware.InitializeOutput, middleware.Metadata, error) {
+	out, metadata, err := next.HandleInitialize(ctx, in)
+
+	operation := fmt.Sprintf(
+		"%s:%s",
+		awsmiddleware.GetServiceID(ctx),
+		awsmiddleware.GetOperationName(ctx),
+	)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.apiCalls[operation]++
+	if err != nil {
+		code := "Unknown"
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) {
+			code = apiErr.ErrorCode()
+		}
+		r.apiErrors[code]++
+	}
+
+	return out, metadata, err
+}
+
+// Resource builds the MiningRun resource, counting the given resources and their properties
+func (r *MiningRun) Resource(resources shared.MinerResources) (shared.MinerResource, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	end := time.Now()
+	resource := shared.MinerResource{Identifier: MiningRunResource}
+	add := func(propertyType, label string, content any) error {
+		property := shared.MinerProperty{
+			Type: propertyType,
+			Label: shared.MinerPropertyLabel{
+				Name:   label,
+				Unique: true,
+			},
+			Content: shared.MinerPropertyContent{
+				Format: shared.FormatJson,
+			},
+		}
+		if err := property.FormatContentValue(CanonicalContent(content)); err != nil {
+			return fmt.Errorf("MiningRun resource %s: %w", propertyType, err)
+		}
+		resource.Properties = append(resource.Properties, property)
+		return nil
+	}
+
+	if err := add(RunTime, RunTime, struct {
+		Start    time.Time
+		End      time.Time
+		Duration string
+	}{r.start, end, formatRunDuration(end.Sub(r.start))}); err != nil {
+		return shared.MinerResource{}, err
+	}
+
+	durations := []struct {
+		propertyType string
+		durations    map[string]*runDuration
+	}{
+		{RunResourceTypeDuration, r.resourceTypes},
+		{RunPropertyTypeDuration, r.propertyTypes},
+	}
+	for _, d := range durations {
+		for name, duration := range d.durations {
+			if err := add(d.propertyType, name, struct {
+				Count    int
+				Duration string
+			}{duration.count, formatRunDuration(duration.duration)}); err != nil {
+				return shared.MinerResource{}, err
+			}
+		}
+	}
+
+	for operation, calls := range r.apiCalls {
+		if err := add(RunApiCalls, operation, struct{ Calls int }{calls}); err != nil {
+			return shared.MinerResource{}, err
+		}
+	}
+	for code, count := range r.apiErrors {
+		if err := add(RunApiErrors, code, struct{ Count int }{count}); err != nil {
+			return shared.MinerResource{}, err
+		}
+	}
+
+	for _, policy := range r.retryPolicies {
+		if err := add(RunRetries, policy.Service, struct {
+			Retries   int64
+			Throttles int64
+		}{policy.retries.Load(), policy.throttles.Load()}); err != nil {
+			return shared.MinerResource{}, err
+		}
+	}
+
+	properties := 0
+	for _, mined := range resources {
+		properties += len(mined.Properties)
+	}
+	if err := add(RunCounts, RunCounts, struct {
+		Resources  int
+		Properties int
+	}{len(resources), properties}); err != nil {
+		return shared.MinerResource{}, err
+	}
+
+	resource.Sort()
+	return resource, nil
+}
+
+func formatRunDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}